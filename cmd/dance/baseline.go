@@ -0,0 +1,169 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/sethvargo/go-githubactions"
+
+	"github.com/FerretDB/dance/internal/baseline"
+	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/pusher"
+	"github.com/FerretDB/dance/internal/results"
+)
+
+// baselineSource provides measurements of previous runs from the push database or the results directory.
+type baselineSource struct {
+	client *pusher.Client // nil for the directory
+	dir    string
+}
+
+// isMongoDBURI returns true if the given baseline source is a MongoDB URI instead of a directory.
+func isMongoDBURI(s string) bool {
+	return strings.HasPrefix(s, "mongodb://") || strings.HasPrefix(s, "mongodb+srv://")
+}
+
+// key returns the key of the given test name in previous results.
+func (bs *baselineSource) key(test string) string {
+	if bs.client != nil {
+		return pusher.TestKey(test)
+	}
+
+	return test
+}
+
+// samples returns measurements of passed tests from up to n previous runs of the given pair
+// on the same runner machine (or host), using results of the given database.
+func (bs *baselineSource) samples(ctx context.Context, pr *pairResult, db string, n int) ([]baseline.Sample, error) {
+	if bs.client != nil {
+		ms, err := bs.client.Fetch(ctx, pr.config, db, n)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make([]baseline.Sample, len(ms))
+		for i, m := range ms {
+			res[i] = m
+		}
+
+		return res, nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := results.History(bs.dir, pr.config, db, hostname, pr.start, n)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]baseline.Sample, len(runs))
+
+	for i, run := range runs {
+		res[i] = make(baseline.Sample)
+
+		for t, tr := range run.Results {
+			if tr.Status == config.Pass && len(tr.Measurements) > 0 {
+				res[i][t] = tr.Measurements
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// checkBaseline compares measurements of the given pair result with the baseline of previous runs.
+// It sets baselineRuns and perf fields of the pair result.
+func (cmd *runCmd) checkBaseline(ctx context.Context, bs *baselineSource, pr *pairResult, l *slog.Logger) error {
+	current := make(map[string]map[string]float64)
+
+	for t, tr := range pr.results {
+		if tr.Status == config.Pass && len(tr.Measurements) > 0 {
+			current[bs.key(t)] = tr.Measurements
+		}
+	}
+
+	if len(current) == 0 {
+		return nil
+	}
+
+	db := cmp.Or(cmd.BaselineDatabase, pr.db)
+
+	samples, err := bs.samples(ctx, pr, db, cmd.BaselineRuns)
+	if err != nil {
+		return fmt.Errorf("failed to get baseline: %w", err)
+	}
+
+	if len(samples) < baseline.MinSamples {
+		l.WarnContext(
+			ctx, "Not enough previous results for the baseline",
+			slog.String("baseline_database", db), slog.Int("runs", len(samples)),
+		)
+
+		return nil
+	}
+
+	pr.baselineRuns = len(samples)
+	pr.perf = baseline.Compare(baseline.Compute(samples), current, cmd.BaselineThreshold)
+
+	return nil
+}
+
+// logPerf logs performance changes of the given pair result relative to the baseline, if it was checked.
+func logPerf(pr *pairResult) {
+	if pr.baselineRuns == 0 {
+		return
+	}
+
+	var regressions int
+
+	if len(pr.perf) > 0 {
+		log.Printf("Performance changes relative to the baseline of %d previous runs:", pr.baselineRuns)
+
+		for _, c := range pr.perf {
+			kind := "improvement"
+			if c.Regression {
+				kind = "regression"
+				regressions++
+			}
+
+			msg := fmt.Sprintf(
+				"%s %s = %g (median %g, MAD %g, z-score %.1f)",
+				c.Test, c.Measurement, c.Value, c.Baseline.Median, c.Baseline.MAD, c.Score,
+			)
+			log.Printf("\t%s: %s", kind, msg)
+
+			// Make regressions visible in the checks UI.
+			if c.Regression && os.Getenv("GITHUB_ACTIONS") == "true" {
+				githubactions.New().WithFieldsMap(map[string]string{
+					"title": fmt.Sprintf("%s/%s: performance regression", pr.config, pr.db),
+				}).Warningf("%s", msg)
+			}
+		}
+
+		log.Print("")
+	}
+
+	log.Printf("Performance regressions: %d, improvements: %d.", regressions, len(pr.perf)-regressions)
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+
+	"github.com/FerretDB/dance/internal/configload"
+)
+
+// blessCmd represents `bless` command.
+//
+//nolint:vet // for readability
+type blessCmd struct {
+	Database string `help:"${help_database}" enum:"${enum_database}" required:"" short:"d"`
+
+	Config string `arg:"" help:"Project configuration to run and rewrite." type:"existingfile"`
+}
+
+// run runs `bless` command.
+func (cmd *blessCmd) run(ctx context.Context, l *slog.Logger) {
+	if err := waitForDBs(ctx, []string{cmd.Database}, cli.ReadyTimeout, l); err != nil {
+		log.Fatal(err)
+	}
+
+	p := pair{config: cmd.Config, db: cmd.Database}
+	rl := l.With(slog.String("config", p.config), slog.String("database", p.db))
+
+	c, err := configload.Load(p.config, p.db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pr, err := runPair(ctx, p, c, &runOpts{verbose: cli.Verbose, perfTolerance: cli.PerfTolerance}, rl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if pr.c == nil {
+		log.Fatalf("No expected results for %s in %s.", p.db, p.config)
+	}
+
+	logResult("Unexpectedly failed", pr.cmp.XFailed, pr)
+	logResult("Unexpectedly skipped", pr.cmp.XSkipped, pr)
+	logResult("Unexpectedly passed", pr.cmp.XPassed, pr)
+	logResult("Failed differently", pr.cmp.FailedDifferently, pr)
+	logResult("Unknown", pr.cmp.Unknown, pr)
+
+	blessed, err := pr.c.Results.Bless(pr.results)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = configload.Bless(p.config, p.db, blessed); err != nil {
+		log.Fatal(err)
+	}
+
+	if n := len(pr.cmp.Unknown); n > 0 {
+		rl.WarnContext(ctx, "Tests with unknown results were not blessed", slog.Int("count", n))
+	}
+
+	if n := len(pr.cmp.FailedDifferently); n > 0 {
+		rl.WarnContext(ctx, "Expected errors of tests that failed differently were not changed", slog.Int("count", n))
+	}
+
+	rl.InfoContext(
+		ctx, "Expected results rewritten",
+		slog.Int("fail", blessed.Stats.Failed), slog.Int("skip", blessed.Stats.Skipped), slog.Int("pass", blessed.Stats.Passed),
+	)
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/configload"
+	"github.com/FerretDB/dance/internal/results"
+)
+
+// compareCmd represents `compare` command.
+//
+//nolint:vet // for readability
+type compareCmd struct {
+	Database string `help:"Use expected results for that database instead of the saved one." short:"d"`
+	Config   string `help:"Use that project configuration instead of the saved one." placeholder:"FILE" type:"path"`
+
+	Results []string `arg:"" help:"Files saved by 'run --save-results'." type:"existingfile"`
+}
+
+// comparePair loads saved results and compares them with expected results.
+func (cmd *compareCmd) comparePair(file string) (*pairResult, error) {
+	run, err := results.Load(file)
+	if err != nil {
+		return nil, err
+	}
+
+	p := pair{config: filepath.Base(run.Config), db: run.Database}
+
+	if cmd.Config != "" {
+		p.config = cmd.Config
+	}
+
+	if cmd.Database != "" {
+		p.db = cmd.Database
+	}
+
+	log.Printf("Comparing %s (%s / %s, %s) with %s / %s.", file, run.Config, run.Database, run.Start, p.config, p.db)
+
+	return compareRun(run, p, cli.PerfTolerance)
+}
+
+// compareRun compares saved results with expected results of the given pair.
+// Bounds of measurements are loosened by the given percentage.
+func compareRun(run *results.Run, p pair, perfTolerance float64) (*pairResult, error) {
+	res := &pairResult{
+		pair: p,
+	}
+
+	c, err := configload.Load(p.config, p.db)
+	if err != nil {
+		return nil, err
+	}
+
+	if c == nil {
+		res.skipped = "no configuration"
+		return res, nil
+	}
+
+	filter, err := config.NewFilter(run.FilterRun, run.FilterSkip)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		applyFilter(c, filter)
+	}
+
+	c.Results.PerfTolerance = perfTolerance
+
+	cmp, err := c.Results.Compare(run.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	res.c = c
+	res.results = run.Results
+	res.cmp = cmp
+	res.start = run.Start
+	res.duration = run.Duration
+	res.shard = run.Shard
+
+	return res, nil
+}
+
+// run runs `compare` command and returns the exit code.
+func (cmd *compareCmd) run(ctx context.Context, l *slog.Logger) int {
+	prs := make([]*pairResult, len(cmd.Results))
+
+	for i, file := range cmd.Results {
+		pr, err := cmd.comparePair(file)
+		if err != nil {
+			l.ErrorContext(ctx, err.Error(), slog.String("file", file))
+			pr = &pairResult{pair: pair{config: file}, err: err}
+		}
+
+		prs[i] = pr
+
+		if pr.err != nil || pr.cmp == nil {
+			continue
+		}
+
+		l.InfoContext(ctx, "Summary", slog.String("config", pr.config), slog.String("database", pr.db))
+
+		if err = logSummary(pr); err != nil {
+			log.Print(err)
+			pr.unexpected = true
+		}
+	}
+
+	logTable(prs)
+
+	return exitCode(prs)
+}
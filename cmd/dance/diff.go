@@ -0,0 +1,136 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/results"
+)
+
+// diffExcerptLines is the maximum number of output lines shown for each diverging test.
+const diffExcerptLines = 10
+
+// diffCmd represents `diff` command.
+//
+//nolint:vet // for readability
+type diffCmd struct {
+	Base    string `help:"Base database."   enum:"${enum_database}" required:""`
+	Target  string `help:"Target database." enum:"${enum_database}" required:""`
+	Results string `help:"Load results saved by 'run --save-results' from that directory." placeholder:"DIR" type:"existingdir"`
+
+	Config string `arg:"" help:"Project configuration to compare." type:"existingfile"`
+}
+
+// load returns actual results for both databases, running tests or loading saved results.
+func (cmd *diffCmd) load(ctx context.Context, l *slog.Logger) (base, target map[string]config.TestResult, err error) {
+	dbs := []string{cmd.Base, cmd.Target}
+	res := make([]map[string]config.TestResult, len(dbs))
+
+	if cmd.Results != "" {
+		for i, db := range dbs {
+			var run *results.Run
+			if run, err = results.Load(filepath.Join(cmd.Results, results.FileName(cmd.Config, db))); err != nil {
+				return
+			}
+
+			res[i] = run.Results
+		}
+
+		return res[0], res[1], nil
+	}
+
+	if err = waitForDBs(ctx, dbs, cli.ReadyTimeout, l); err != nil {
+		return
+	}
+
+	cf := filepath.Base(cmd.Config)
+	pairs := []pair{{config: cf, db: cmd.Base}, {config: cf, db: cmd.Target}}
+
+	for i, pr := range runPairs(ctx, pairs, &runOpts{verbose: cli.Verbose}, 1, l) {
+		if pr.err != nil {
+			err = pr.err
+			return
+		}
+
+		if pr.results == nil {
+			err = fmt.Errorf("no configuration for %s", pr.db)
+			return
+		}
+
+		res[i] = pr.results
+	}
+
+	return res[0], res[1], nil
+}
+
+// excerpt returns the last lines of the test output.
+func excerpt(tr *config.TestResult) string {
+	if tr == nil {
+		return "(not run)"
+	}
+
+	lines := strings.Split(strings.TrimSpace(tr.Output), "\n")
+	if len(lines) > diffExcerptLines {
+		lines = append([]string{"..."}, lines[len(lines)-diffExcerptLines:]...)
+	}
+
+	return strings.Join(lines, "\n\t")
+}
+
+// run runs `diff` command.
+func (cmd *diffCmd) run(ctx context.Context, l *slog.Logger) {
+	base, target, err := cmd.load(ctx, l)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dr := config.Diff(base, target)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "TEST\t%s\t%s\n", strings.ToUpper(cmd.Base), strings.ToUpper(cmd.Target))
+
+	for _, e := range dr.Entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", e.Test, e.BaseStatus(), e.TargetStatus())
+	}
+
+	_ = w.Flush()
+	log.Print(buf.String())
+
+	for _, e := range dr.Entries {
+		log.Printf("===> %s:", e.Test)
+		log.Printf("%s (%s):\n\t%s", cmd.Base, e.BaseStatus(), excerpt(e.Base))
+		log.Printf("%s (%s):\n\t%s", cmd.Target, e.TargetStatus(), excerpt(e.Target))
+	}
+
+	log.Printf("Same status: %d.", dr.Same)
+	log.Printf("Different status: %d.", len(dr.Entries))
+
+	transitions := dr.Transitions()
+	for _, t := range slices.Sorted(maps.Keys(transitions)) {
+		log.Printf("\t%s: %d.", t, transitions[t])
+	}
+}
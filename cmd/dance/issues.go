@@ -0,0 +1,132 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/configload"
+)
+
+// issuesCmd represents `issues` command.
+//
+//nolint:vet // for readability
+type issuesCmd struct {
+	Database []string `help:"${help_database}" enum:"${enum_database}" short:"d"`
+
+	Config []string `arg:"" help:"Project configurations to check." optional:"" type:"existingfile"`
+}
+
+// issueEntry represents a single entry of expected results that references an issue.
+type issueEntry struct {
+	issue  string
+	config string
+	db     string
+	status config.Status
+	name   string
+	reason string
+}
+
+// issueEntries returns entries of expected results that reference issues.
+func issueEntries(file, db string, expected *config.ExpectedResults) []issueEntry {
+	var res []issueEntry
+
+	for _, g := range []struct {
+		status  config.Status
+		entries []string
+	}{
+		{config.Fail, expected.Fail},
+		{config.Skip, expected.Skip},
+		{config.Pass, expected.Pass},
+		{config.Ignore, expected.Ignore},
+	} {
+		for _, e := range g.entries {
+			info := expected.Info[e]
+			if info.Issue == "" {
+				continue
+			}
+
+			res = append(res, issueEntry{
+				issue:  info.Issue,
+				config: file,
+				db:     db,
+				status: g.status,
+				name:   e,
+				reason: info.Reason,
+			})
+		}
+	}
+
+	return res
+}
+
+// run runs `issues` command.
+func (cmd *issuesCmd) run(ctx context.Context, l *slog.Logger) {
+	if len(cmd.Database) == 0 {
+		cmd.Database = slices.Sorted(maps.Keys(configload.DBs))
+	}
+
+	if len(cmd.Config) == 0 {
+		var err error
+		if cmd.Config, err = filepath.Glob("*.yml"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var entries []issueEntry
+
+	for _, cf := range cmd.Config {
+		cf = filepath.Base(cf)
+
+		for _, db := range cmd.Database {
+			c, err := configload.Load(cf, db)
+			if err != nil {
+				l.ErrorContext(ctx, err.Error(), slog.String("config", cf), slog.String("database", db))
+				os.Exit(1)
+			}
+
+			if c != nil {
+				entries = append(entries, issueEntries(cf, db, c.Results)...)
+			}
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b issueEntry) int {
+		return cmp.Or(cmp.Compare(a.issue, b.issue), cmp.Compare(a.config, b.config), cmp.Compare(a.db, b.db))
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "ISSUE\tCONFIG\tDATABASE\tSTATUS\tTEST\tREASON")
+
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.issue, e.config, e.db, e.status, e.name, e.reason)
+	}
+
+	_ = w.Flush()
+
+	issues := slices.CompactFunc(slices.Clone(entries), func(a, b issueEntry) bool { return a.issue == b.issue })
+	log.Printf("%d issues referenced by %d entries.", len(issues), len(entries))
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/configload"
+	"github.com/FerretDB/dance/internal/runner"
+)
+
+// listCmd represents `list` command.
+//
+//nolint:vet // for readability
+type listCmd struct {
+	Database []string `help:"${help_database}" enum:"${enum_database}" short:"d"`
+
+	Config []string `arg:"" help:"Project configurations to list tests of." optional:"" type:"existingfile"`
+}
+
+// listConfig returns names of tests of the given project configuration
+// and expected results for each database that has them.
+func (cmd *listCmd) listConfig(
+	ctx context.Context, file string, l *slog.Logger,
+) ([]string, map[string]*config.ExpectedResults, error) {
+	var r runner.Runner
+
+	expected := make(map[string]*config.ExpectedResults)
+
+	for _, db := range cmd.Database {
+		c, err := configload.Load(file, db)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if c == nil {
+			continue
+		}
+
+		expected[db] = c.Results
+
+		// test names do not depend on the database
+		if r == nil {
+			if r, err = newRunner(c, l, cli.Verbose); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if r == nil {
+		return nil, nil, nil
+	}
+
+	lr, ok := r.(runner.Lister)
+	if !ok {
+		l.WarnContext(ctx, "Runner can't list tests without running them, skipping")
+		return nil, nil, nil
+	}
+
+	tests, err := lr.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tests, expected, nil
+}
+
+// run runs `list` command.
+func (cmd *listCmd) run(ctx context.Context, l *slog.Logger) {
+	if len(cmd.Database) == 0 {
+		cmd.Database = slices.Sorted(maps.Keys(configload.DBs))
+	}
+
+	if len(cmd.Config) == 0 {
+		var err error
+		if cmd.Config, err = filepath.Glob("*.yml"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintf(w, "CONFIG\tTEST\t%s\n", strings.ToUpper(strings.Join(cmd.Database, "\t")))
+
+	var failed bool
+
+	for _, cf := range cmd.Config {
+		cf = filepath.Base(cf)
+
+		tests, expected, err := cmd.listConfig(ctx, cf, l.With(slog.String("config", cf)))
+		if err != nil {
+			l.ErrorContext(ctx, err.Error(), slog.String("config", cf))
+			failed = true
+
+			continue
+		}
+
+		for _, test := range tests {
+			statuses := make([]string, len(cmd.Database))
+
+			for i, db := range cmd.Database {
+				statuses[i] = "-"
+
+				e := expected[db]
+				if e == nil {
+					continue
+				}
+
+				s, err := e.Status(test)
+				if err != nil {
+					l.ErrorContext(ctx, err.Error(), slog.String("config", cf), slog.String("database", db))
+					failed = true
+
+					continue
+				}
+
+				statuses[i] = string(s)
+			}
+
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", cf, test, strings.Join(statuses, "\t"))
+		}
+	}
+
+	_ = w.Flush()
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// logLevel returns the log level set by the flag.
+func logLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cli.LogLevel)); err != nil {
+		panic(err) // checked by enum
+	}
+
+	return level
+}
+
+// newLogHandler returns a new log handler with the level and format set by flags.
+func newLogHandler(w io.Writer) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level: logLevel(),
+	}
+
+	if cli.LogFormat == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.NewTextHandler(w, opts)
+}
+
+// setupLogging configures the default logger with flags.
+//
+// The default text handler is kept for the console; other handlers also get `log` package output.
+// In both cases, `log` package output (summaries, tables, etc.) is not filtered by level.
+func setupLogging() {
+	if cli.LogFormat == "text" {
+		slog.SetLogLoggerLevel(logLevel())
+		return
+	}
+
+	h := newLogHandler(os.Stderr)
+
+	slog.SetDefault(slog.New(h))
+
+	// replace the output set by SetDefault that filters by level
+	log.SetOutput(&unleveledWriter{h: h})
+}
+
+// unleveledWriter is an [io.Writer] for `log` package that passes each message to the handler
+// as an INFO record regardless of the handler's level.
+type unleveledWriter struct {
+	h slog.Handler
+}
+
+// Write implements [io.Writer] interface.
+func (uw *unleveledWriter) Write(b []byte) (int, error) {
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, strings.TrimSuffix(string(b), "\n"), 0)
+
+	// Handle does not check the level, unlike Logger
+	if err := uw.h.Handle(context.Background(), r); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// pairLogPath returns the path of the log file for the given pair in the given directory, creating the directory.
+func pairLogPath(dir string, p pair) (string, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return "", err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(p.config), filepath.Ext(p.config)) + "_" + p.db + ".log"
+
+	return filepath.Join(dir, name), nil
+}
+
+// pairLogFile creates a log file for the given pair in the given directory.
+func pairLogFile(dir string, p pair) (*os.File, error) {
+	file, err := pairLogPath(dir, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Create(file)
+}
+
+// appendPairLogFile opens the log file of the given pair in the given directory for appending.
+func appendPairLogFile(dir string, p pair) (*os.File, error) {
+	file, err := pairLogPath(dir, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o666)
+}
+
+// teePairLog makes `log` package output (summaries, etc.) also appended to the log file
+// of the given pair in the given directory.
+// The returned function restores the previous output; it should be called before logging for other pairs.
+func teePairLog(dir string, p pair) (func(), error) {
+	f, err := appendPairLogFile(dir, p)
+	if err != nil {
+		return nil, err
+	}
+
+	w := log.Writer()
+	log.SetOutput(io.MultiWriter(w, &unleveledWriter{h: newLogHandler(f)}))
+
+	return func() {
+		log.SetOutput(w)
+		_ = f.Close()
+	}, nil
+}
+
+// multiHandler is a [slog.Handler] that passes records to all handlers.
+type multiHandler []slog.Handler
+
+// Enabled implements [slog.Handler] interface.
+func (mh multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range mh {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle implements [slog.Handler] interface.
+func (mh multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+
+	for _, h := range mh {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements [slog.Handler] interface.
+func (mh multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	res := make(multiHandler, len(mh))
+	for i, h := range mh {
+		res[i] = h.WithAttrs(attrs)
+	}
+
+	return res
+}
+
+// WithGroup implements [slog.Handler] interface.
+func (mh multiHandler) WithGroup(name string) slog.Handler {
+	res := make(multiHandler, len(mh))
+	for i, h := range mh {
+		res[i] = h.WithGroup(name)
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ io.Writer    = (*unleveledWriter)(nil)
+	_ slog.Handler = multiHandler(nil)
+)
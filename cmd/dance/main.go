@@ -15,17 +15,17 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"log/slog"
 	"maps"
-	"net"
-	"net/url"
 	"os"
 	"path/filepath"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
@@ -34,31 +34,19 @@ import (
 	"github.com/sethvargo/go-githubactions"
 	"gopkg.in/yaml.v3"
 
+	"github.com/FerretDB/dance/internal/baseline"
 	"github.com/FerretDB/dance/internal/config"
 	"github.com/FerretDB/dance/internal/configload"
 	"github.com/FerretDB/dance/internal/pusher"
-	"github.com/FerretDB/dance/internal/runner"
-	"github.com/FerretDB/dance/internal/runner/command"
-	"github.com/FerretDB/dance/internal/runner/gotest"
-	"github.com/FerretDB/dance/internal/runner/ycsb"
+	"github.com/FerretDB/dance/internal/report"
 )
 
-func waitForPort(ctx context.Context, port int) error {
-	for ctx.Err() == nil {
-		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
-		if err == nil {
-			return conn.Close()
-		}
-
-		sleepCtx, sleepCancel := context.WithTimeout(ctx, time.Second)
-		<-sleepCtx.Done()
-		sleepCancel()
-	}
+// consoleOutputLines is the maximum number of the last output lines logged for tests with artifacts.
+const consoleOutputLines = 20
 
-	return ctx.Err()
-}
-
-func logResult(label string, res map[string]config.TestResult) {
+// logResult logs tests of the given pair result with their outputs and information of expected results entries.
+// Outputs of tests with artifact files are truncated.
+func logResult(label string, res map[string]config.TestResult, pr *pairResult) {
 	keys := slices.Sorted(maps.Keys(res))
 	if len(keys) == 0 {
 		return
@@ -68,10 +56,25 @@ func logResult(label string, res map[string]config.TestResult) {
 	for _, t := range keys {
 		log.Printf("===> %s:", t)
 
-		if o := res[t].Output; o != "" {
+		if info, ok := pr.cmp.Info[t]; ok {
+			log.Printf("\tExpected because: %s", info)
+		}
+
+		o := res[t].Output
+
+		file := pr.artifacts[t]
+		if file != "" && o != "" {
+			o = strings.ReplaceAll(report.TruncateOutput(report.RawOutput(res[t]), consoleOutputLines), "\n", "\n\t")
+		}
+
+		if o != "" {
 			log.Printf("\t%s", o)
 		}
 
+		if file != "" {
+			log.Printf("\tFull output: %s", file)
+		}
+
 		if m := res[t].Measurements; m != nil {
 			log.Printf("\tMeasurements: %v", m)
 		}
@@ -80,40 +83,346 @@ func logResult(label string, res map[string]config.TestResult) {
 	}
 }
 
+// logPairSummary logs the summary of the given pair result with [logSummary],
+// also writing it to the log file of the pair if --log-dir flag is set.
+// Unexpected results are recorded in the pair result; the returned error is only for the log file.
+func logPairSummary(pr *pairResult) error {
+	var err error
+
+	if cli.LogDir != "" {
+		var restore func()
+		if restore, err = teePairLog(cli.LogDir, pr.pair); err == nil {
+			defer restore()
+		}
+	}
+
+	if summaryErr := logSummary(pr); summaryErr != nil {
+		log.Print(summaryErr)
+		pr.unexpected = true
+	}
+
+	return err
+}
+
+// logReasons logs information of expected results entries for the given tests that have it.
+func logReasons(label string, res map[string]config.TestResult, pr *pairResult) {
+	var keys []string
+
+	for _, t := range slices.Sorted(maps.Keys(res)) {
+		if _, ok := pr.cmp.Info[t]; ok {
+			keys = append(keys, t)
+		}
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	log.Printf("%s tests with reasons:", label)
+	for _, t := range keys {
+		log.Printf("\t%s: %s", t, pr.cmp.Info[t])
+	}
+
+	log.Print("")
+}
+
+// githubOutputLines is the maximum number of the last output lines in GitHub Actions annotations.
+const githubOutputLines = 10
+
+// logSummary logs the summary of the given pair result.
+// It returns an error if actual stats do not match expected stats,
+// or if there are stale expectations and --strict-expectations flag is set.
+func logSummary(pr *pairResult) error {
+	cmp := pr.cmp
+
+	logResult("Unexpectedly failed", cmp.XFailed, pr)
+	logResult("Unexpectedly skipped", cmp.XSkipped, pr)
+	logResult("Unexpectedly passed", cmp.XPassed, pr)
+	logResult("Failed differently", cmp.FailedDifferently, pr)
+
+	if cli.Verbose {
+		logResult("Expectedly failed", cmp.Failed, pr)
+		logResult("Expectedly skipped", cmp.Skipped, pr)
+		logResult("Expectedly passed", cmp.Passed, pr)
+	} else {
+		logReasons("Expectedly failed", cmp.Failed, pr)
+	}
+
+	logResult("Unknown", cmp.Unknown, pr)
+	logResult("Flaky", cmp.Flaky, pr)
+
+	if cli.Verbose && len(cmp.Ignored) > 0 {
+		log.Print("Ignored tests:")
+
+		for _, t := range slices.Sorted(maps.Keys(cmp.Ignored)) {
+			log.Printf("\t%s: %s", cmp.Ignored[t].Status, t)
+		}
+
+		log.Print("")
+	}
+
+	stale := pr.stale()
+	if len(stale) > 0 {
+		log.Print("Stale expectations (entries that matched no tests):")
+
+		for _, e := range slices.Sorted(maps.Keys(stale)) {
+			log.Printf("\t%s: %s", stale[e], e)
+		}
+
+		log.Print("")
+	}
+
+	// Make unexpected failures visible in the checks UI.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		action := githubactions.New()
+
+		for _, t := range slices.Sorted(maps.Keys(cmp.XFailed)) {
+			action.WithFieldsMap(map[string]string{
+				"title": fmt.Sprintf("%s/%s: %s unexpectedly failed", pr.config, pr.db, t),
+			}).Errorf("%s", report.TruncateOutput(report.RawOutput(cmp.XFailed[t]), githubOutputLines))
+		}
+
+		for _, t := range slices.Sorted(maps.Keys(cmp.FailedDifferently)) {
+			action.WithFieldsMap(map[string]string{
+				"title": fmt.Sprintf("%s/%s: %s failed differently", pr.config, pr.db, t),
+			}).Errorf("%s", report.TruncateOutput(report.RawOutput(cmp.FailedDifferently[t]), githubOutputLines))
+		}
+	}
+
+	log.Printf("Unexpectedly failed: %d.", len(cmp.XFailed))
+	log.Printf("Unexpectedly skipped: %d.", len(cmp.XSkipped))
+	log.Printf("Unexpectedly passed: %d.", len(cmp.XPassed))
+	log.Printf("Failed differently: %d.", len(cmp.FailedDifferently))
+	log.Printf("Expectedly failed: %d.", len(cmp.Failed))
+	log.Printf("Expectedly skipped: %d.", len(cmp.Skipped))
+	log.Printf("Expectedly passed: %d.", len(cmp.Passed))
+	log.Printf("Unknown: %d.", len(cmp.Unknown))
+	log.Printf("Flaky (expected results after retries): %d.", len(cmp.Flaky))
+	log.Printf(
+		"Ignored: %d (failed: %d, skipped: %d, passed: %d).",
+		len(cmp.Ignored), cmp.Stats.IgnoredFailed, cmp.Stats.IgnoredSkipped, cmp.Stats.IgnoredPassed,
+	)
+	log.Printf("Stale expectations: %d.", len(stale))
+
+	logPerf(pr)
+
+	expected := pr.c.Results.Stats
+
+	// only unexpected results are checked for partial runs
+	if pr.partial() {
+		log.Print("Only some tests were run; expected stats are not checked.")
+
+		expected = &config.Stats{
+			Failed:  cmp.Stats.Failed,
+			Skipped: cmp.Stats.Skipped,
+			Passed:  cmp.Stats.Passed,
+		}
+	}
+
+	expectedStats, err := yaml.Marshal(expected)
+	if err != nil {
+		return err
+	}
+
+	actualStats, err := yaml.Marshal(cmp.Stats)
+	if err != nil {
+		return err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expectedStats)),
+		B:        difflib.SplitLines(string(actualStats)),
+		FromFile: "Expected",
+		ToFile:   "Actual",
+		Context:  10,
+	})
+	if err != nil {
+		return err
+	}
+
+	if diff != "" {
+		return fmt.Errorf("\nUnexpected stats:\n%s", diff)
+	}
+
+	if totalRun := cmp.Stats.Failed + cmp.Stats.Skipped + cmp.Stats.Passed; totalRun > 0 {
+		msg := fmt.Sprintf(
+			"%.2f%% (%d/%d) tests passed.",
+			float64(cmp.Stats.Passed)/float64(totalRun)*100,
+			cmp.Stats.Passed,
+			totalRun,
+		)
+		log.Print(msg)
+
+		// Make percentage more visible on GitHub Actions.
+		// https://docs.github.com/en/actions/learn-github-actions/variables#default-environment-variables
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			action := githubactions.New()
+			action.Noticef("%s", msg)
+		}
+	}
+
+	if len(stale) > 0 && cli.StrictExpectations {
+		return fmt.Errorf("\nStale expectations: %d", len(stale))
+	}
+
+	return nil
+}
+
 //nolint:vet // for readability
 var cli struct {
+	Verbose bool `help:"Be more verbose." short:"v"`
+
+	StrictExpectations bool    `help:"Fail if some entries of expected results matched no tests."`
+	PerfTolerance      float64 `help:"Loosen all bounds of measurements by the given percentage." placeholder:"PERCENT"`
+
+	LogLevel  string `default:"debug" enum:"debug,info,warn,error" help:"Log level: ${enum}."`
+	LogFormat string `default:"text"  enum:"text,json"             help:"Log format: ${enum}."`
+
+	LogDir string `help:"Also write logs of each pair to a separate file in the given directory." placeholder:"DIR" type:"path"`
+
+	Databases string `default:"${default_databases}" help:"${help_databases}" placeholder:"FILE" type:"path"`
+
+	ReadyTimeout time.Duration `default:"5m" help:"Wait up to that duration for each database to be ready."`
+
+	Run      runCmd      `cmd:"" default:"withargs" help:"Run project configurations."`
+	Bless    blessCmd    `cmd:""                    help:"Run project configuration and rewrite expected results."`
+	Validate validateCmd `cmd:""                    help:"Validate project configurations without running them."`
+	Compare  compareCmd  `cmd:""                    help:"Compare saved raw results with expected results."`
+	Diff     diffCmd     `cmd:""                    help:"Show tests with different results for two databases."`
+	Merge    mergeCmd    `cmd:""                    help:"Merge saved results of shards and compare them with expected results."`
+	List     listCmd     `cmd:""                    help:"List tests of project configurations with expected results."`
+	Issues   issuesCmd   `cmd:""                    help:"List issues referenced by expected results."`
+}
+
+// runCmd represents `run` command.
+//
+//nolint:vet // for readability
+type runCmd struct {
 	Database []string `help:"${help_database}" enum:"${enum_database}"               short:"d"`
-	Verbose  bool     `help:"Be more verbose." short:"v"`
 	Push     string   `help:"Push results to the given MongoDB URI."`
-	Config   []string `arg:""                  help:"Project configurations to run." optional:"" type:"existingfile"`
+	Parallel int      `default:"1"             help:"Run up to N configuration and database pairs in parallel." placeholder:"N"`
+
+	Run  string `help:"Run only tests with names matching the regular expression." placeholder:"REGEX"`
+	Skip string `help:"Do not run tests with names matching the regular expression." placeholder:"REGEX"`
+
+	Retries *int   `help:"Retry tests with unexpected results up to N times; overrides configuration value." placeholder:"N"`
+	Shard   string `help:"Run only the I-th of N parts of tests, for example, 1/4."                          placeholder:"I/N"`
+
+	JUnitReport string `name:"junit-report" help:"Write JUnit XML report to the given file." placeholder:"FILE" type:"path"`
+	JSONReport  string `name:"json-report"  help:"Write JSON Lines report to the given file." placeholder:"FILE" type:"path"`
+	SaveResults string `help:"Save raw results to the given directory for 'compare' command." placeholder:"DIR" type:"path"`
+	Artifacts   string `help:"Write full output of each test to the given directory." placeholder:"DIR" type:"path"`
+
+	Baseline          string  `help:"Compare measurements with previous runs in URI or DIR."              placeholder:"URI|DIR"`
+	BaselineDatabase  string  `help:"Use previous results of that database instead."                      placeholder:"DB"`
+	BaselineRuns      int     `help:"Use up to N latest previous results."                   default:"10" placeholder:"N"`
+	BaselineThreshold float64 `help:"Report changes with robust z-score of at least Z."      default:"3"  placeholder:"Z"`
+
+	Config []string `arg:"" help:"Project configurations to run." optional:"" type:"existingfile"`
+}
+
+// defaultDatabases is the default databases file.
+const defaultDatabases = "databases.yml"
+
+// databasesFile returns the databases file set by the flag or the environment variable in the given arguments.
+//
+// It is needed before the CLI is parsed because enum and help for databases depend on that file.
+func databasesFile(args []string) (file string, explicit bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if v, ok := strings.CutPrefix(arg, "--databases="); ok {
+			return v, true
+		}
+
+		if arg == "--databases" && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+
+	if v := os.Getenv("DANCE_DATABASES"); v != "" {
+		return v, true
+	}
+
+	return defaultDatabases, false
+}
+
+// loadDBs adds databases from the file, if any, to built-in databases.
+func loadDBs(args []string) error {
+	file, explicit := databasesFile(args)
+
+	if !explicit {
+		if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+	}
+
+	dbs, err := configload.LoadDBs(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	configload.DBs = dbs
+
+	return nil
 }
 
-func parseCLI() {
+func parseCLI() *kong.Context {
+	if err := loadDBs(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+
 	dbs := slices.Sorted(maps.Keys(configload.DBs))
 
 	dbsHelp := make([]string, len(dbs))
-	for i, db := range dbs {
-		dbsHelp[i] = fmt.Sprintf("%s (%s)", db, configload.DBs[db])
+	for i, name := range dbs {
+		db := configload.DBs[name]
+
+		attrs := []string{db.URI}
+
+		if db.Auth {
+			attrs = append(attrs, "auth")
+		}
+
+		if db.ReplSet != "" {
+			attrs = append(attrs, "replica set "+db.ReplSet)
+		}
+
+		if db.Version != "" {
+			attrs = append(attrs, "version "+db.Version)
+		}
+
+		dbsHelp[i] = fmt.Sprintf("%s (%s)", name, strings.Join(attrs, ", "))
 	}
 
 	kongOptions := []kong.Option{
 		kong.Vars{
-			"help_database": fmt.Sprintf("Database: %s.", strings.Join(dbsHelp, ", ")),
-			"enum_database": strings.Join(dbs, ","),
+			"help_database":     fmt.Sprintf("Database: %s.", strings.Join(dbsHelp, ", ")),
+			"enum_database":     strings.Join(dbs, ","),
+			"default_databases": defaultDatabases,
+			"help_databases": "Read databases from the given file in addition to built-in ones; " +
+				"the default file is optional.",
 		},
 		kong.DefaultEnvars("DANCE"),
 	}
 
-	kong.Parse(&cli, kongOptions...)
+	return kong.Parse(&cli, kongOptions...)
 }
 
 func main() {
 	log.SetFlags(0)
-	slog.SetLogLoggerLevel(slog.LevelDebug)
 
-	l := slog.Default()
+	kctx := parseCLI()
+
+	if cli.PerfTolerance < 0 {
+		log.Fatal("--perf-tolerance must not be negative")
+	}
 
-	parseCLI()
+	setupLogging()
+
+	l := slog.Default()
 
 	ctx, stop := sigTerm(context.Background())
 
@@ -125,164 +434,254 @@ func main() {
 		stop()
 	}()
 
+	switch cmd := kctx.Command(); cmd {
+	case "run", "run <config>":
+		if code := cli.Run.run(ctx, l); code != 0 {
+			os.Exit(code)
+		}
+	case "bless <config>":
+		cli.Bless.run(ctx, l)
+	case "validate", "validate <config>":
+		cli.Validate.run(ctx, l)
+	case "diff <config>":
+		cli.Diff.run(ctx, l)
+	case "compare <results>":
+		if code := cli.Compare.run(ctx, l); code != 0 {
+			os.Exit(code)
+		}
+	case "list", "list <config>":
+		cli.List.run(ctx, l)
+	case "issues", "issues <config>":
+		cli.Issues.run(ctx, l)
+	case "merge <results>":
+		if code := cli.Merge.run(ctx, l); code != 0 {
+			os.Exit(code)
+		}
+	default:
+		panic(fmt.Sprintf("unknown command %q", cmd))
+	}
+}
+
+// Exit codes of `run` command.
+const (
+	exitUnexpected = 1 // expectations are not met
+	exitError      = 2 // runner or infrastructure failure
+)
+
+// run runs `run` command and returns the exit code.
+func (cmd *runCmd) run(ctx context.Context, l *slog.Logger) int {
+	filter, err := config.NewFilter(cmd.Run, cmd.Skip)
+	if err != nil {
+		l.ErrorContext(ctx, err.Error())
+		return exitError
+	}
+
+	if cmd.Retries != nil && *cmd.Retries < 0 {
+		l.ErrorContext(ctx, "--retries must not be negative")
+		return exitError
+	}
+
+	var shard *config.Shard
+
+	if cmd.Shard != "" {
+		if shard, err = config.ParseShard(cmd.Shard); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			return exitError
+		}
+	}
+
 	var pusherClient *pusher.Client
 
-	if cli.Push != "" {
-		var err error
-		if pusherClient, err = pusher.New(cli.Push, l.With(slog.String("name", "pusher"))); err != nil {
-			log.Fatal(err)
+	if cmd.Push != "" {
+		if pusherClient, err = pusher.New(cmd.Push, l.With(slog.String("name", "pusher"))); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			return exitError
 		}
 
 		defer pusherClient.Close()
 	}
 
-	if len(cli.Database) == 0 {
-		cli.Database = slices.Sorted(maps.Keys(configload.DBs))
-	}
+	var bs *baselineSource
+
+	if cmd.Baseline != "" {
+		if cmd.BaselineRuns < baseline.MinSamples || cmd.BaselineThreshold <= 0 {
+			l.ErrorContext(ctx, fmt.Sprintf(
+				"--baseline-runs must be at least %d, and --baseline-threshold must be positive", baseline.MinSamples,
+			))
 
-	for _, db := range cli.Database {
-		uri := configload.DBs[db]
-		u, err := url.Parse(uri)
-		if err != nil {
-			log.Fatal(err)
+			return exitError
 		}
 
-		port, err := strconv.Atoi(u.Port())
-		if err != nil {
-			log.Fatal(err)
+		bs = &baselineSource{dir: cmd.Baseline}
+
+		switch {
+		case !isMongoDBURI(cmd.Baseline):
+			if _, err = os.Stat(cmd.Baseline); err != nil {
+				l.ErrorContext(ctx, err.Error())
+				return exitError
+			}
+
+		case cmd.Baseline == cmd.Push:
+			bs.client = pusherClient
+
+		default:
+			if bs.client, err = pusher.New(cmd.Baseline, l.With(slog.String("name", "baseline"))); err != nil {
+				l.ErrorContext(ctx, err.Error())
+				return exitError
+			}
+
+			defer bs.client.Close()
 		}
+	}
+
+	if len(cmd.Database) == 0 {
+		cmd.Database = slices.Sorted(maps.Keys(configload.DBs))
+	}
 
-		log.Printf("Waiting for port %d for %s / %s to be up...", port, db, uri)
+	if err := waitForDBs(ctx, cmd.Database, cli.ReadyTimeout, l); err != nil {
+		l.ErrorContext(ctx, err.Error())
+		return exitError
+	}
 
-		if err = waitForPort(ctx, port); err != nil {
-			log.Fatal(err)
+	if len(cmd.Config) == 0 {
+		if cmd.Config, err = filepath.Glob("*.yml"); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			return exitError
 		}
 	}
 
-	if len(cli.Config) == 0 {
-		var err error
-		if cli.Config, err = filepath.Glob("*.yml"); err != nil {
-			log.Fatal(err)
+	for i, cf := range cmd.Config {
+		cmd.Config[i] = filepath.Base(cf)
+	}
+
+	log.Printf("Run project configs: %v", cmd.Config)
+
+	var pairs []pair
+
+	for _, cf := range cmd.Config {
+		for _, db := range cmd.Database {
+			pairs = append(pairs, pair{config: cf, db: db})
 		}
 	}
 
-	for i, cf := range cli.Config {
-		cli.Config[i] = filepath.Base(cf)
+	if cmd.Parallel > 1 {
+		log.Printf("Running up to %d pairs in parallel.", cmd.Parallel)
 	}
 
-	log.Printf("Run project configs: %v", cli.Config)
+	opts := &runOpts{
+		filter:  filter,
+		shard:   shard,
+		retries: cmd.Retries,
+		logDir:  cli.LogDir,
+		verbose: cli.Verbose,
 
-	for _, cf := range cli.Config {
-		for _, db := range cli.Database {
-			rl := l.With(slog.String("config", cf), slog.String("database", db))
+		perfTolerance: cli.PerfTolerance,
+	}
 
-			c, err := configload.Load(cf, db)
-			if err != nil {
-				rl.Error(err.Error())
-				os.Exit(1)
-			}
+	results := runPairs(ctx, pairs, opts, cmd.Parallel, l)
 
-			if c == nil {
-				rl.Warn("No configuration, skipping")
-				continue
-			}
+	var code int
 
-			rl.Info("Configuration loaded")
+	if cmd.Artifacts != "" {
+		if err := writeArtifacts(cmd.Artifacts, results); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			code = exitError
+		}
+	}
 
-			var runner runner.Runner
+	if cmd.SaveResults != "" {
+		if err := saveResults(cmd.SaveResults, results, filter); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			code = exitError
+		}
+	}
 
-			switch c.Runner {
-			case config.RunnerTypeCommand:
-				runner, err = command.New(c.Params.(*config.RunnerParamsCommand), rl, cli.Verbose)
-			case config.RunnerTypeGoTest:
-				runner, err = gotest.New(c.Params.(*config.RunnerParamsGoTest), rl, cli.Verbose)
-			case config.RunnerTypeYCSB:
-				runner, err = ycsb.New(c.Params.(*config.RunnerParamsYCSB), rl)
-			default:
-				log.Fatalf("unknown runner: %q", c.Runner)
+	if bs != nil {
+		for _, pr := range results {
+			if pr.err != nil || pr.cmp == nil {
+				continue
 			}
 
-			if err != nil {
-				log.Fatal(err)
+			rl := l.With(slog.String("config", pr.config), slog.String("database", pr.db))
+			if err := cmd.checkBaseline(ctx, bs, pr, rl); err != nil {
+				rl.WarnContext(ctx, err.Error())
 			}
+		}
+	}
 
-			res, err := runner.Run(ctx)
-			if err != nil {
-				log.Fatal(err)
-			}
+	// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#adding-a-job-summary
+	if os.Getenv("GITHUB_STEP_SUMMARY") != "" {
+		var buf bytes.Buffer
+		if err := report.WriteMarkdown(&buf, reportResults(results)); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			code = exitError
+		} else {
+			githubactions.New().AddStepSummary(buf.String())
+		}
+	}
 
-			cmp, err := c.Results.Compare(res)
-			if err != nil {
-				log.Fatal(err)
-			}
+	if cmd.JUnitReport != "" {
+		if err := writeReport(cmd.JUnitReport, results, report.WriteJUnit); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			code = exitError
+		} else {
+			log.Printf("JUnit report written to %s.", cmd.JUnitReport)
+		}
+	}
 
-			logResult("Unexpectedly failed", cmp.XFailed)
-			logResult("Unexpectedly skipped", cmp.XSkipped)
-			logResult("Unexpectedly passed", cmp.XPassed)
+	if cmd.JSONReport != "" {
+		if err := writeReport(cmd.JSONReport, results, report.WriteJSON); err != nil {
+			l.ErrorContext(ctx, err.Error())
+			code = exitError
+		} else {
+			log.Printf("JSON report written to %s.", cmd.JSONReport)
+		}
+	}
 
-			if cli.Verbose {
-				logResult("Expectedly failed", cmp.Failed)
-				logResult("Expectedly skipped", cmp.Skipped)
-				logResult("Expectedly passed", cmp.Passed)
-			}
+	for _, pr := range results {
+		if pr.err != nil || pr.cmp == nil {
+			continue
+		}
 
-			logResult("Unknown", cmp.Unknown)
+		rl := l.With(slog.String("config", pr.config), slog.String("database", pr.db))
+		rl.InfoContext(ctx, "Summary")
 
-			log.Printf("Unexpectedly failed: %d.", len(cmp.XFailed))
-			log.Printf("Unexpectedly skipped: %d.", len(cmp.XSkipped))
-			log.Printf("Unexpectedly passed: %d.", len(cmp.XPassed))
-			log.Printf("Expectedly failed: %d.", len(cmp.Failed))
-			log.Printf("Expectedly skipped: %d.", len(cmp.Skipped))
-			log.Printf("Expectedly passed: %d.", len(cmp.Passed))
-			log.Printf("Unknown: %d.", len(cmp.Unknown))
+		if err := logPairSummary(pr); err != nil {
+			rl.ErrorContext(ctx, err.Error())
+		}
 
-			expectedStats, err := yaml.Marshal(c.Results.Stats)
-			if err != nil {
-				log.Fatal(err)
-			}
+		if pr.unexpected {
+			continue
+		}
 
-			actualStats, err := yaml.Marshal(cmp.Stats)
-			if err != nil {
-				log.Fatal(err)
+		// results of shards without tests are not pushed
+		if pusherClient != nil && len(pr.results) > 0 {
+			// TODO https://github.com/FerretDB/dance/issues/1122
+			if err := pusherClient.Push(ctx, pr.config, pr.db, pr.cmp.Passed, pr.cmp.Ignored); err != nil {
+				rl.ErrorContext(ctx, err.Error())
+				pr.err = err
 			}
+		}
+	}
 
-			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
-				A:        difflib.SplitLines(string(expectedStats)),
-				B:        difflib.SplitLines(string(actualStats)),
-				FromFile: "Expected",
-				ToFile:   "Actual",
-				Context:  10,
-			})
-			if err != nil {
-				log.Fatal(err)
-			}
+	logTable(results)
 
-			if diff != "" {
-				log.Fatalf("\nUnexpected stats:\n%s", diff)
-			}
+	// infrastructure errors take precedence
+	return max(code, exitCode(results))
+}
 
-			totalRun := cmp.Stats.Failed + cmp.Stats.Skipped + cmp.Stats.Passed
-			msg := fmt.Sprintf(
-				"%.2f%% (%d/%d) tests passed.",
-				float64(cmp.Stats.Passed)/float64(totalRun)*100,
-				cmp.Stats.Passed,
-				totalRun,
-			)
-			log.Print(msg)
-
-			// Make percentage more visible on GitHub Actions.
-			// https://docs.github.com/en/actions/learn-github-actions/variables#default-environment-variables
-			if os.Getenv("GITHUB_ACTIONS") == "true" {
-				action := githubactions.New()
-				action.Noticef("%s", msg)
-			}
+// exitCode returns the exit code for the given results.
+func exitCode(results []*pairResult) int {
+	var code int
 
-			if pusherClient != nil {
-				// TODO https://github.com/FerretDB/dance/issues/1122
-				if err = pusherClient.Push(ctx, cf, db, cmp.Passed); err != nil {
-					log.Fatal(err)
-				}
-			}
+	for _, pr := range results {
+		switch {
+		case pr.err != nil:
+			code = exitError
+		case pr.unexpected:
+			code = max(code, exitUnexpected)
 		}
 	}
+
+	return code
 }
@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/FerretDB/dance/internal/results"
+)
+
+// mergeCmd represents `merge` command.
+//
+//nolint:vet // for readability
+type mergeCmd struct {
+	SaveResults string `help:"Save merged raw results to the given directory." placeholder:"DIR" type:"path"`
+
+	Results []string `arg:"" help:"Files of all shards saved by 'run --shard --save-results'." type:"existingfile"`
+}
+
+// mergePair merges results of all shards of a single pair and compares them with expected results.
+func (cmd *mergeCmd) mergePair(p pair, runs []*results.Run) (*pairResult, error) {
+	run, err := results.Merge(runs)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Merged %d shards of %s / %s: %d tests.", len(runs), p.config, p.db, len(run.Results))
+
+	if cmd.SaveResults != "" {
+		file, err := results.Save(cmd.SaveResults, run)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("Results saved to %s.", file)
+	}
+
+	return compareRun(run, p, cli.PerfTolerance)
+}
+
+// run runs `merge` command and returns the exit code.
+func (cmd *mergeCmd) run(ctx context.Context, l *slog.Logger) int {
+	var pairs []pair
+	runs := make(map[pair][]*results.Run)
+
+	for _, file := range cmd.Results {
+		run, err := results.Load(file)
+		if err != nil {
+			l.ErrorContext(ctx, err.Error())
+			return exitError
+		}
+
+		p := pair{config: filepath.Base(run.Config), db: run.Database}
+
+		if _, ok := runs[p]; !ok {
+			pairs = append(pairs, p)
+		}
+
+		runs[p] = append(runs[p], run)
+	}
+
+	prs := make([]*pairResult, len(pairs))
+
+	for i, p := range pairs {
+		pr, err := cmd.mergePair(p, runs[p])
+		if err != nil {
+			l.ErrorContext(ctx, err.Error())
+			pr = &pairResult{pair: p, err: err}
+		}
+
+		prs[i] = pr
+
+		if pr.err != nil || pr.cmp == nil {
+			continue
+		}
+
+		l.InfoContext(ctx, "Summary", slog.String("config", pr.config), slog.String("database", pr.db))
+
+		if err = logSummary(pr); err != nil {
+			log.Print(err)
+			pr.unexpected = true
+		}
+	}
+
+	logTable(prs)
+
+	return exitCode(prs)
+}
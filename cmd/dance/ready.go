@@ -0,0 +1,159 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"github.com/FerretDB/dance/internal/configload"
+)
+
+// serverInfo represents information reported by the server.
+type serverInfo struct {
+	version  string // MongoDB version
+	ferretDB string // FerretDB version, if any
+	replSet  string // replica set name, if any
+	topology string
+}
+
+// probeDB connects to the database, runs `hello` and `ping` commands, and returns server information.
+//
+// Credentials from the URI are used, so `ping` fails if authentication does.
+func probeDB(ctx context.Context, uri string) (*serverInfo, error) {
+	opts := options.Client().ApplyURI(uri)
+	opts.SetConnectTimeout(3 * time.Second)
+	opts.SetServerSelectionTimeout(3 * time.Second)
+
+	c, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Disconnect(ctx) //nolint:errcheck // we are only reading
+
+	if err = c.Ping(ctx, readpref.Primary()); err != nil {
+		return nil, err
+	}
+
+	admin := c.Database("admin")
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	if err = admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, fmt.Errorf("hello: %w", err)
+	}
+
+	var buildInfo struct {
+		Version  string `bson:"version"`
+		FerretDB *struct {
+			Version string `bson:"version"`
+		} `bson:"ferretdb"`
+	}
+
+	if err = admin.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo); err != nil {
+		return nil, fmt.Errorf("buildInfo: %w", err)
+	}
+
+	info := &serverInfo{
+		version:  buildInfo.Version,
+		replSet:  hello.SetName,
+		topology: "standalone",
+	}
+
+	if buildInfo.FerretDB != nil {
+		info.ferretDB = buildInfo.FerretDB.Version
+	}
+
+	switch {
+	case hello.SetName != "":
+		info.topology = "replica set " + hello.SetName
+	case hello.Msg == "isdbgrid":
+		info.topology = "sharded cluster"
+	}
+
+	return info, nil
+}
+
+// waitForDB waits for the database to be ready or for the timeout.
+func waitForDB(ctx context.Context, name string, timeout time.Duration, l *slog.Logger) error {
+	db := configload.DBs[name]
+
+	u, err := url.Parse(db.URI)
+	if err != nil {
+		return err
+	}
+
+	if db.Auth && u.User == nil {
+		return fmt.Errorf("%s: authentication is enabled, but MongoDB URI %s has no credentials", name, u.Redacted())
+	}
+
+	log.Printf("Waiting for %s / %s to be ready...", name, u.Redacted())
+
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errors.New("ready timeout"))
+	defer cancel()
+
+	for {
+		info, err := probeDB(ctx, db.URI)
+		if err == nil {
+			version := info.version
+			if info.ferretDB != "" {
+				version = fmt.Sprintf("%s (FerretDB %s)", version, info.ferretDB)
+			}
+
+			log.Printf("%s is ready: version %s, %s.", name, version, info.topology)
+
+			if err = db.Check(info.version, info.replSet); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+
+			return nil
+		}
+
+		l.DebugContext(ctx, "Database is not ready", slog.String("database", name), slog.String("error", err.Error()))
+
+		sleepCtx, sleepCancel := context.WithTimeout(ctx, time.Second)
+		<-sleepCtx.Done()
+		sleepCancel()
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s is not ready: %w (%w)", name, context.Cause(ctx), err)
+		}
+	}
+}
+
+// waitForDBs waits for the given databases to be ready.
+func waitForDBs(ctx context.Context, dbs []string, timeout time.Duration, l *slog.Logger) error {
+	for _, db := range dbs {
+		if err := waitForDB(ctx, db, timeout, l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,716 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/FerretDB/dance/internal/artifacts"
+	"github.com/FerretDB/dance/internal/baseline"
+	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/configload"
+	"github.com/FerretDB/dance/internal/report"
+	"github.com/FerretDB/dance/internal/results"
+	"github.com/FerretDB/dance/internal/runner"
+	"github.com/FerretDB/dance/internal/runner/command"
+	"github.com/FerretDB/dance/internal/runner/gotest"
+	"github.com/FerretDB/dance/internal/runner/ycsb"
+)
+
+// pair represents a single combination of project configuration file and database.
+type pair struct {
+	config string
+	db     string
+}
+
+// runOpts represents options for running pairs.
+type runOpts struct {
+	filter  *config.Filter // nil if all tests should be run
+	shard   *config.Shard  // nil if all tests should be run
+	retries *int           // overrides configuration value if not nil
+	logDir  string         // directory for log files of pairs, if set
+	verbose bool
+
+	perfTolerance float64 // percentage by which bounds of measurements are loosened
+}
+
+// pairResult represents the outcome of running a single pair.
+type pairResult struct {
+	pair
+
+	// all are nil if the pair was not run
+	c       *config.Config
+	results map[string]config.TestResult
+	cmp     *config.CompareResults
+
+	start    time.Time
+	duration time.Duration
+
+	// only that part of tests was run, if set
+	shard *config.Shard
+
+	// artifact files with full outputs by test name, if written
+	artifacts map[string]string
+
+	// number of previous runs used for the baseline; 0 if measurements were not compared with it
+	baselineRuns int
+
+	// significant performance changes relative to the baseline
+	perf []baseline.Change
+
+	// reason why the pair was not run, if set
+	skipped string
+
+	// runner or infrastructure error; other fields are nil if it is set
+	err error
+
+	// set if actual stats do not match expected stats
+	unexpected bool
+}
+
+// dirLocks tracks directories of running configurations.
+//
+// It is not safe for concurrent use.
+type dirLocks struct {
+	running map[string]int  // number of running configurations by directory
+	serial  map[string]bool // directories of running configurations with serial flag
+}
+
+// tryLock locks the directory of the given configuration and returns true
+// if that configuration could run together with already running ones.
+//
+// Serial configurations exclude all others with the same directory; others exclude only serial ones.
+// Nil configuration (that is not run) is never excluded.
+func (dl *dirLocks) tryLock(c *config.Config) bool {
+	if c == nil {
+		return true
+	}
+
+	if dl.running == nil {
+		dl.running = make(map[string]int)
+		dl.serial = make(map[string]bool)
+	}
+
+	dir := paramsDir(c.Params)
+
+	if dl.serial[dir] || (c.Serial && dl.running[dir] > 0) {
+		return false
+	}
+
+	dl.running[dir]++
+	dl.serial[dir] = c.Serial
+
+	return true
+}
+
+// unlock unlocks the directory of the given configuration locked by tryLock.
+func (dl *dirLocks) unlock(c *config.Config) {
+	if c == nil {
+		return
+	}
+
+	dir := paramsDir(c.Params)
+
+	if dl.running[dir]--; dl.running[dir] == 0 {
+		delete(dl.running, dir)
+		delete(dl.serial, dir)
+	}
+}
+
+// paramsDir returns the directory of the given runner parameters.
+func paramsDir(p config.RunnerParams) string {
+	switch p := p.(type) {
+	case *config.RunnerParamsCommand:
+		return p.Dir
+	case *config.RunnerParamsGoTest:
+		return p.Dir
+	case *config.RunnerParamsYCSB:
+		return p.Dir
+	default:
+		panic(fmt.Sprintf("unknown runner parameters type %T", p))
+	}
+}
+
+// newRunner creates a new runner for the given configuration.
+func newRunner(c *config.Config, l *slog.Logger, verbose bool) (runner.Runner, error) {
+	switch c.Runner {
+	case config.RunnerTypeCommand:
+		return command.New(c.Params.(*config.RunnerParamsCommand), l, verbose)
+	case config.RunnerTypeGoTest:
+		return gotest.New(c.Params.(*config.RunnerParamsGoTest), l, verbose)
+	case config.RunnerTypeYCSB:
+		return ycsb.New(c.Params.(*config.RunnerParamsYCSB), l)
+	default:
+		return nil, fmt.Errorf("unknown runner: %q", c.Runner)
+	}
+}
+
+// applyFilter changes the configuration to run only tests selected by the filter.
+// It returns false if no tests are selected.
+//
+// Runners that can't select tests by name run all of them; other results are ignored by comparison.
+func applyFilter(c *config.Config, filter *config.Filter) bool {
+	c.Results.Filter = filter
+
+	switch p := c.Params.(type) {
+	case *config.RunnerParamsCommand:
+		p.Tests = slices.DeleteFunc(p.Tests, func(t config.RunnerParamsCommandTest) bool {
+			return !filter.Match(t.Name)
+		})
+
+		return len(p.Tests) > 0
+
+	case *config.RunnerParamsGoTest:
+		// go test matches each slash-separated part of the name without the package path separately,
+		// so results are not filtered again by full names
+		if filter.Run != nil {
+			p.Run = filter.Run.String()
+		}
+
+		if filter.Skip != nil {
+			p.Skip = filter.Skip.String()
+		}
+
+		f := *filter
+		f.ByRunner = true
+		c.Results.Filter = &f
+
+	case *config.RunnerParamsYCSB:
+	}
+
+	return true
+}
+
+// applyShard changes the configuration to run only tests of the given shard.
+// It returns false if there are no tests in that shard, so the configuration should not be run at all.
+//
+// Runners that can't select tests by name run all of them in the shard selected by the configuration file name.
+func applyShard(c *config.Config, shard *config.Shard, file string) bool {
+	switch p := c.Params.(type) {
+	case *config.RunnerParamsCommand:
+		p.Tests = slices.DeleteFunc(p.Tests, func(t config.RunnerParamsCommandTest) bool {
+			return !shard.Match(t.Name)
+		})
+
+		return len(p.Tests) > 0
+
+	case *config.RunnerParamsGoTest:
+		p.Shard = shard
+
+	case *config.RunnerParamsYCSB:
+		return shard.Match(file)
+	}
+
+	return true
+}
+
+// partial returns true if only some tests of the configuration were run.
+func (pr *pairResult) partial() bool {
+	return pr.c.Results.Filter != nil || pr.shard != nil
+}
+
+// stale returns entries of expected results that matched no tests, or nil for partial runs.
+func (pr *pairResult) stale() map[string]config.Status {
+	if pr.partial() {
+		return nil
+	}
+
+	return pr.cmp.Stale
+}
+
+// retry runs tests with unexpected results again up to the given number of times.
+//
+// Actual results are updated in place with the last attempt and the output of all attempts.
+func retry(
+	ctx context.Context, r runner.Runner, retries int, l *slog.Logger,
+	expected *config.ExpectedResults, actual map[string]config.TestResult, cmp *config.CompareResults,
+) (*config.CompareResults, error) {
+	rr, ok := r.(runner.Rerunner)
+	if !ok {
+		l.WarnContext(ctx, "Runner does not support retries")
+		return cmp, nil
+	}
+
+	for attempt := 2; attempt <= retries+1 && ctx.Err() == nil; attempt++ {
+		var tests []string
+
+		for _, m := range []map[string]config.TestResult{
+			cmp.XFailed, cmp.XSkipped, cmp.XPassed, cmp.FailedDifferently, cmp.Unknown,
+		} {
+			tests = append(tests, slices.Collect(maps.Keys(m))...)
+		}
+
+		if len(tests) == 0 {
+			break
+		}
+
+		slices.Sort(tests)
+
+		l.InfoContext(ctx, "Retrying tests with unexpected results", slog.Int("attempt", attempt), slog.Any("tests", tests))
+
+		rerun, err := rr.Rerun(ctx, tests)
+		if err != nil {
+			return nil, err
+		}
+
+		for test, tr := range rerun {
+			prev := actual[test]
+
+			if prev.Attempts == 0 {
+				prev.Attempts = 1
+				prev.Output = fmt.Sprintf("--- Attempt 1: %s\n%s", prev.Status, prev.Output)
+			}
+
+			tr.Attempts = prev.Attempts + 1
+			tr.Output = fmt.Sprintf("%s\n--- Attempt %d: %s\n%s", prev.Output, tr.Attempts, tr.Status, tr.Output)
+
+			actual[test] = tr
+		}
+
+		if cmp, err = expected.Compare(actual); err != nil {
+			return nil, err
+		}
+	}
+
+	return cmp, nil
+}
+
+// addNotRun adds failed timed out results for tests that were not run before the configuration timed out
+// (for example, during setup) to actual results, and returns them.
+//
+// Tests are listed by the runner if it can do that, or taken from expected results otherwise.
+func addNotRun(
+	ctx context.Context, r runner.Runner, c *config.Config,
+	actual map[string]config.TestResult, runErr error, l *slog.Logger,
+) map[string]config.TestResult {
+	var tests []string
+
+	if lr, ok := r.(runner.Lister); ok {
+		// the context is already done
+		lctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+
+		var err error
+		if tests, err = lr.List(lctx); err != nil {
+			l.WarnContext(ctx, "Failed to list tests that were not run", slog.String("error", err.Error()))
+		}
+	}
+
+	if len(tests) == 0 {
+		tests = c.Results.Names()
+	}
+
+	output := "not run: " + context.Cause(ctx).Error()
+	if runErr != nil {
+		output += "\n" + runErr.Error()
+	}
+
+	if actual == nil {
+		actual = make(map[string]config.TestResult, len(tests))
+	}
+
+	for _, t := range tests {
+		if _, ok := actual[t]; !ok {
+			actual[t] = config.TestResult{Status: config.Fail, Output: output, TimedOut: true}
+		}
+	}
+
+	return actual
+}
+
+// runPair runs the given configuration of the pair and compares results.
+// Configuration is nil if there are no expected results for the database.
+func runPair(ctx context.Context, p pair, c *config.Config, opts *runOpts, l *slog.Logger) (*pairResult, error) {
+	res := &pairResult{
+		pair: p,
+	}
+
+	if c == nil {
+		l.WarnContext(ctx, "No configuration, skipping")
+		res.skipped = "no configuration"
+		return res, nil
+	}
+
+	l.InfoContext(ctx, "Configuration loaded")
+
+	c.Results.PerfTolerance = opts.perfTolerance
+
+	if opts.filter != nil && !applyFilter(c, opts.filter) {
+		l.InfoContext(ctx, "No tests match filters, skipping")
+		res.skipped = "no tests match filters"
+		return res, nil
+	}
+
+	if opts.shard != nil {
+		res.shard = opts.shard
+
+		if !applyShard(c, opts.shard, p.config) {
+			l.InfoContext(ctx, "No tests in this shard, skipping", slog.String("shard", opts.shard.String()))
+
+			// empty results are still compared and saved, so results of all shards could be merged
+			actual := make(map[string]config.TestResult)
+
+			cmp, err := c.Results.Compare(actual)
+			if err != nil {
+				return nil, err
+			}
+
+			res.c = c
+			res.results = actual
+			res.cmp = cmp
+			res.start = time.Now()
+
+			return res, nil
+		}
+	}
+
+	r, err := newRunner(c, l, opts.verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, c.Timeout, fmt.Errorf("configuration timed out after %s", c.Timeout))
+
+		defer cancel()
+	}
+
+	res.start = time.Now()
+
+	tr, err := r.Run(ctx)
+
+	// tests that were not run before the configuration timed out are reported instead of being lost
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if tr = addNotRun(ctx, r, c, tr, err, l); len(tr) > 0 {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cmp, err := c.Results.Compare(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	retries := c.Retries
+	if opts.retries != nil {
+		retries = *opts.retries
+	}
+
+	if retries > 0 {
+		if cmp, err = retry(ctx, r, retries, l, c.Results, tr, cmp); err != nil {
+			return nil, err
+		}
+	}
+
+	res.duration = time.Since(res.start)
+
+	res.c = c
+	res.results = tr
+	res.cmp = cmp
+
+	return res, nil
+}
+
+// runPairs runs all pairs with up to parallel pairs at the same time.
+// Results are returned in the same order as pairs.
+// Failure of one pair does not prevent others from running; it is recorded in the result instead.
+//
+// Pairs are started in order, but pairs that can't run yet because of serial configurations
+// sharing the same directory are passed over, so they do not occupy workers while waiting.
+//
+// When more than one pair runs at the same time, the log output of each pair
+// is buffered and written at once when that pair finishes.
+func runPairs(ctx context.Context, pairs []pair, opts *runOpts, parallel int, l *slog.Logger) []*pairResult {
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	// configurations are loaded upfront to know their directories;
+	// loading errors are reported when pairs are run
+	configs := make([]*config.Config, len(pairs))
+	loadErrs := make([]error, len(pairs))
+
+	for i, p := range pairs {
+		configs[i], loadErrs[i] = configload.Load(p.config, p.db)
+	}
+
+	res := make([]*pairResult, len(pairs))
+
+	var outM sync.Mutex
+
+	run := func(p pair, c *config.Config, err error) *pairResult {
+		rl := l
+
+		if parallel > 1 {
+			var buf runner.LockedBuffer
+
+			rl = slog.New(newLogHandler(&buf))
+
+			defer func() {
+				outM.Lock()
+				defer outM.Unlock()
+
+				_, _ = os.Stderr.Write(buf.Bytes())
+			}()
+		}
+
+		// do not start new pairs after termination signal
+		if err == nil {
+			err = context.Cause(ctx)
+		}
+
+		if err == nil && opts.logDir != "" {
+			var f *os.File
+			if f, err = pairLogFile(opts.logDir, p); err == nil {
+				defer f.Close()
+
+				rl = slog.New(multiHandler{rl.Handler(), newLogHandler(f)})
+			}
+		}
+
+		rl = rl.With(slog.String("config", p.config), slog.String("database", p.db))
+
+		var pr *pairResult
+		if err == nil {
+			pr, err = runPair(ctx, p, c, opts, rl)
+		}
+
+		if err != nil {
+			rl.ErrorContext(ctx, err.Error())
+			pr = &pairResult{pair: p, err: err}
+		}
+
+		return pr
+	}
+
+	var m sync.Mutex
+	cond := sync.NewCond(&m)
+
+	var locks dirLocks
+	pending := make([]int, len(pairs))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	// next returns the index of the first pending pair that could run now,
+	// waiting for running pairs to finish if needed; it returns false if there are no pending pairs
+	next := func() (int, bool) {
+		m.Lock()
+		defer m.Unlock()
+
+		for len(pending) > 0 {
+			for j, i := range pending {
+				if locks.tryLock(configs[i]) {
+					pending = slices.Delete(pending, j, j+1)
+					return i, true
+				}
+			}
+
+			cond.Wait()
+		}
+
+		return 0, false
+	}
+
+	var g errgroup.Group
+
+	for range min(parallel, len(pairs)) {
+		g.Go(func() error {
+			for {
+				i, ok := next()
+				if !ok {
+					return nil
+				}
+
+				res[i] = run(pairs[i], configs[i], loadErrs[i])
+
+				m.Lock()
+				locks.unlock(configs[i])
+				cond.Broadcast()
+				m.Unlock()
+			}
+		})
+	}
+
+	_ = g.Wait()
+
+	return res
+}
+
+// reportResults converts pair results to report results, skipping pairs without configuration.
+func reportResults(results []*pairResult) []*report.Result {
+	res := make([]*report.Result, 0, len(results))
+
+	for _, pr := range results {
+		if pr.err != nil || pr.cmp == nil {
+			continue
+		}
+
+		// expected stats and stale entries are not known for partial runs
+		expected := pr.c.Results.Stats
+		cmp := pr.cmp
+
+		if pr.partial() {
+			expected = nil
+
+			c := *cmp
+			c.Stale = nil
+			cmp = &c
+		}
+
+		res = append(res, &report.Result{
+			Config:   pr.config,
+			Database: pr.db,
+			Expected: expected,
+			Compare:  cmp,
+			Duration: pr.duration,
+		})
+	}
+
+	return res
+}
+
+// writeReport writes report of the given results to the file using the given function.
+func writeReport(file string, results []*pairResult, write func(io.Writer, []*report.Result) error) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err = write(f, reportResults(results)); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// logTable logs a table with the outcome of each pair.
+// If --log-dir flag is set, the outcome is also written to the log file of each pair.
+func logTable(results []*pairResult) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "CONFIG\tDATABASE\tRESULT")
+
+	for _, pr := range results {
+		var res string
+
+		switch {
+		case pr.err != nil:
+			res = "infrastructure error: " + strings.SplitN(pr.err.Error(), "\n", 2)[0]
+		case pr.cmp == nil:
+			res = pr.skipped
+		case pr.unexpected:
+			res = "unexpected results"
+		default:
+			res = "OK"
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", pr.config, pr.db, res)
+
+		if cli.LogDir != "" {
+			if f, err := appendPairLogFile(cli.LogDir, pr.pair); err == nil {
+				_, _ = fmt.Fprintf(&unleveledWriter{h: newLogHandler(f)}, "Result of %s / %s: %s", pr.config, pr.db, res)
+				_ = f.Close()
+			}
+		}
+	}
+
+	_ = w.Flush()
+	log.Print(buf.String())
+}
+
+// writeArtifacts writes full outputs of all tests of all pairs that were run to the given directory.
+func writeArtifacts(dir string, res []*pairResult) error {
+	for _, pr := range res {
+		if pr.results == nil {
+			continue
+		}
+
+		var err error
+		if pr.artifacts, err = artifacts.Write(dir, pr.config, pr.db, pr.results); err != nil {
+			return err
+		}
+
+		log.Printf("Artifacts of %s / %s written to %s.", pr.config, pr.db, dir)
+	}
+
+	return nil
+}
+
+// saveResults saves raw results of all pairs that were run to the given directory.
+func saveResults(dir string, res []*pairResult, filter *config.Filter) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	for _, pr := range res {
+		if pr.results == nil {
+			continue
+		}
+
+		run := &results.Run{
+			Config:     pr.config,
+			Database:   pr.db,
+			Runner:     pr.c.Runner,
+			Start:      pr.start,
+			Duration:   pr.duration,
+			Hostname:   hostname,
+			Repository: os.Getenv("GITHUB_REPOSITORY"),
+			Commit:     os.Getenv("GITHUB_SHA"),
+			Shard:      pr.shard,
+			Results:    pr.results,
+		}
+
+		if filter != nil && filter.Run != nil {
+			run.FilterRun = filter.Run.String()
+		}
+
+		if filter != nil && filter.Skip != nil {
+			run.FilterSkip = filter.Skip.String()
+		}
+
+		file, err := results.Save(dir, run)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Results saved to %s.", file)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FerretDB/dance/internal/configload"
+)
+
+// validateCmd represents `validate` command.
+//
+//nolint:vet // for readability
+type validateCmd struct {
+	Workflows string `default:"../.github/workflows" help:"GitHub Actions workflows with databases run on CI." type:"path"`
+
+	Config []string `arg:"" help:"Project configurations to validate." optional:"" type:"existingfile"`
+}
+
+// workflowDatabases returns sorted `db` values of job matrices from GitHub Actions workflows in the given directory.
+func workflowDatabases(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dbs := make(map[string]struct{})
+
+	add := func(v any) {
+		switch v := v.(type) {
+		case string:
+			dbs[v] = struct{}{}
+		case []any:
+			for _, e := range v {
+				if s, ok := e.(string); ok {
+					dbs[s] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); e.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var wf struct {
+			Jobs map[string]struct {
+				Strategy struct {
+					Matrix yaml.Node `yaml:"matrix"`
+				} `yaml:"strategy"`
+			} `yaml:"jobs"`
+		}
+
+		if err = yaml.Unmarshal(b, &wf); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+
+		for _, job := range wf.Jobs {
+			var matrix struct {
+				DB      any              `yaml:"db"`
+				Include []map[string]any `yaml:"include"`
+			}
+
+			// matrix may be an expression
+			if job.Strategy.Matrix.Kind != yaml.MappingNode || job.Strategy.Matrix.Decode(&matrix) != nil {
+				continue
+			}
+
+			add(matrix.DB)
+
+			for _, i := range matrix.Include {
+				add(i["db"])
+			}
+		}
+	}
+
+	if len(dbs) == 0 {
+		return nil, nil
+	}
+
+	return slices.Sorted(maps.Keys(dbs)), nil
+}
+
+// run runs `validate` command.
+func (cmd *validateCmd) run(ctx context.Context, l *slog.Logger) {
+	run, err := workflowDatabases(cmd.Workflows)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Fatal(err)
+		}
+
+		l.WarnContext(ctx, "Workflows not found, not checking databases that are run", slog.String("dir", cmd.Workflows))
+	}
+
+	if err == nil && run == nil {
+		l.WarnContext(ctx, "No databases in workflows, not checking databases that are run", slog.String("dir", cmd.Workflows))
+	}
+
+	if len(cmd.Config) == 0 {
+		if cmd.Config, err = filepath.Glob("*.yml"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var problems int
+
+	for _, cf := range cmd.Config {
+		errs := configload.Validate(cf, run)
+		for _, err = range errs {
+			log.Printf("%s: %s", filepath.Base(cf), err)
+		}
+
+		problems += len(errs)
+	}
+
+	if problems > 0 {
+		log.Fatalf("Found %d problems in %d project configs.", problems, len(cmd.Config))
+	}
+
+	log.Printf("All %d project configs are valid.", len(cmd.Config))
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package artifacts provides writing of full test outputs and measurements to files.
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// maxNameLength is the maximum length of the file name without extension.
+const maxNameLength = 200
+
+// unsafeRE matches characters that are replaced in file names.
+var unsafeRE = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitize returns a file name without extension for the given test name.
+//
+// Names that are too long are truncated; a hash of the test name is added to keep them unique.
+func sanitize(test string) string {
+	res := strings.Trim(unsafeRE.ReplaceAllString(test, "_"), "._")
+	if res != "" && len(res) <= maxNameLength {
+		return res
+	}
+
+	return withHash(res[:min(len(res), maxNameLength-9)], test)
+}
+
+// withHash returns the given file name with a hash of the test name.
+func withHash(name, test string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(test))
+
+	return fmt.Sprintf("%s_%08x", name, h.Sum32())
+}
+
+// Write writes the output of each test to `dir/<config>/<db>/<test>.log` file,
+// and measurements, if any, to `<test>.json` file next to it.
+//
+// It returns paths of output files by test name.
+func Write(dir, configFile, db string, results map[string]config.TestResult) (map[string]string, error) {
+	d := filepath.Join(dir, strings.TrimSuffix(filepath.Base(configFile), filepath.Ext(configFile)), db)
+
+	if err := os.MkdirAll(d, 0o777); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string, len(results))
+	used := make(map[string]string, len(results))
+
+	for _, test := range slices.Sorted(maps.Keys(results)) {
+		name := sanitize(test)
+
+		// different test names could be sanitized to the same file name
+		if other, ok := used[name]; ok {
+			name = withHash(name, test)
+
+			if _, ok = used[name]; ok {
+				return nil, fmt.Errorf("can't find unique file name for %q and %q", test, other)
+			}
+		}
+
+		used[name] = test
+
+		tr := results[test]
+
+		file := filepath.Join(d, name+".log")
+		if err := os.WriteFile(file, []byte(tr.Output), 0o666); err != nil {
+			return nil, err
+		}
+
+		res[test] = file
+
+		if tr.Measurements == nil {
+			continue
+		}
+
+		b, err := json.MarshalIndent(tr.Measurements, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		if err = os.WriteFile(filepath.Join(d, name+".json"), append(b, '\n'), 0o666); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	for test, expected := range map[string]string{
+		"test1":                         "test1",
+		"pkg/TestDumpRestore/sample_1":  "pkg_TestDumpRestore_sample_1",
+		"tests/test_a.py::Test::test b": "tests_test_a.py_Test_test_b",
+		"../etc/passwd":                 "etc_passwd",
+		"///":                           "_1d37d324",
+	} {
+		assert.Equal(t, expected, sanitize(test), "test = %q", test)
+	}
+
+	long := sanitize(strings.Repeat("a", 300))
+	assert.Len(t, long, maxNameLength)
+	assert.NotEqual(t, long, sanitize(strings.Repeat("a", 301)))
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	results := map[string]config.TestResult{
+		"a/b": {Status: config.Fail, Output: "output 1\n"},
+		"a_b": {Status: config.Pass, Output: "output 2\n"},
+		"read": {
+			Status:       config.Pass,
+			Measurements: map[string]float64{"ops": 1234.5},
+		},
+	}
+
+	actual, err := Write(dir, "ycsb-workloada.yml", "ferretdb2", results)
+	require.NoError(t, err)
+
+	d := filepath.Join(dir, "ycsb-workloada", "ferretdb2")
+	expected := map[string]string{
+		"a/b":  filepath.Join(d, "a_b.log"),
+		"a_b":  filepath.Join(d, "a_b_1ba46871.log"),
+		"read": filepath.Join(d, "read.log"),
+	}
+	assert.Equal(t, expected, actual)
+
+	b, err := os.ReadFile(expected["a_b"])
+	require.NoError(t, err)
+	assert.Equal(t, "output 2\n", string(b))
+
+	b, err = os.ReadFile(filepath.Join(d, "read.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ops": 1234.5}`, string(b))
+}
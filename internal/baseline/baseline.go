@@ -0,0 +1,175 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package baseline provides detection of performance changes relative to previous runs.
+package baseline
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Sample represents measurements of a single previous run by test name and measurement name.
+type Sample map[string]map[string]float64
+
+const (
+	// MinSamples is the minimal number of previous values of a measurement required to detect changes.
+	MinSamples = 3
+
+	// madScale makes MAD a consistent estimator of the standard deviation for normally distributed values.
+	madScale = 1.4826
+
+	// minRelativeScale is the minimal scale relative to the median,
+	// so changes of stable measurements (with zero MAD) by less than a few percents are not significant.
+	minRelativeScale = 0.01
+)
+
+// Stats represents a baseline of a single measurement.
+type Stats struct {
+	Median float64
+	MAD    float64 // median absolute deviation
+	N      int     // number of values
+}
+
+// Change represents a significant change of a single measurement relative to the baseline.
+//
+//nolint:vet // for readability
+type Change struct {
+	Test        string
+	Measurement string
+	Value       float64
+	Baseline    Stats
+	Score       float64 // robust z-score; positive if the value is greater than the median
+	Regression  bool    // false for improvements
+}
+
+// higherIsBetter returns true if greater values of the given measurement are better
+// (throughput), false if smaller values are better (durations and latencies).
+// It returns ok = false for other measurements, like counts, that are not checked.
+//
+// Names are the ones produced by `ycsb` runner.
+func higherIsBetter(measurement string) (res, ok bool) {
+	switch {
+	case measurement == "ops":
+		return true, true
+	case measurement == "takes", measurement == "avg", measurement == "min", measurement == "max":
+		return false, true
+	case strings.HasPrefix(measurement, "perc"):
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// median returns the median of the given values; they are sorted in place.
+func median(values []float64) float64 {
+	slices.Sort(values)
+
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// Compute returns baselines by test name and measurement name for the given samples.
+func Compute(samples []Sample) map[string]map[string]Stats {
+	values := make(map[string]map[string][]float64)
+
+	for _, s := range samples {
+		for test, ms := range s {
+			if values[test] == nil {
+				values[test] = make(map[string][]float64)
+			}
+
+			for name, v := range ms {
+				values[test][name] = append(values[test][name], v)
+			}
+		}
+	}
+
+	res := make(map[string]map[string]Stats, len(values))
+
+	for test, ms := range values {
+		res[test] = make(map[string]Stats, len(ms))
+
+		for name, vs := range ms {
+			m := median(vs)
+
+			deviations := make([]float64, len(vs))
+			for i, v := range vs {
+				deviations[i] = math.Abs(v - m)
+			}
+
+			res[test][name] = Stats{
+				Median: m,
+				MAD:    median(deviations),
+				N:      len(vs),
+			}
+		}
+	}
+
+	return res
+}
+
+// Compare returns significant changes of the given measurements by test name relative to baselines,
+// sorted by test and measurement names.
+//
+// A change is significant if the absolute value of its robust z-score is at least the given threshold.
+// Measurements with less than [MinSamples] previous values are not checked.
+func Compare(baselines map[string]map[string]Stats, current map[string]map[string]float64, threshold float64) []Change {
+	var res []Change
+
+	for test, ms := range current {
+		for name, v := range ms {
+			higher, ok := higherIsBetter(name)
+			if !ok {
+				continue
+			}
+
+			b, ok := baselines[test][name]
+			if !ok || b.N < MinSamples {
+				continue
+			}
+
+			scale := max(madScale*b.MAD, minRelativeScale*math.Abs(b.Median))
+			if scale == 0 {
+				continue
+			}
+
+			score := (v - b.Median) / scale
+			if math.Abs(score) < threshold {
+				continue
+			}
+
+			res = append(res, Change{
+				Test:        test,
+				Measurement: name,
+				Value:       v,
+				Baseline:    b,
+				Score:       score,
+				Regression:  (score < 0) == higher,
+			})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(a.Test, b.Test), cmp.Compare(a.Measurement, b.Measurement))
+	})
+
+	return res
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package baseline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	var samples []Sample
+
+	for i, ops := range []float64{1000, 1010, 990, 1005, 995} {
+		s := Sample{
+			"read": {"ops": ops, "avg": 0.001, "perc99": 0.01, "count": ops * 10},
+		}
+
+		if i < MinSamples-1 {
+			s["insert"] = map[string]float64{"ops": 500}
+		}
+
+		samples = append(samples, s)
+	}
+
+	baselines := Compute(samples)
+
+	assert.Equal(t, Stats{Median: 1000, MAD: 5, N: 5}, baselines["read"]["ops"])
+	assert.Equal(t, Stats{Median: 0.01, MAD: 0, N: 5}, baselines["read"]["perc99"])
+	assert.Equal(t, Stats{Median: 500, MAD: 0, N: 2}, baselines["insert"]["ops"])
+
+	for name, tc := range map[string]struct {
+		current  map[string]map[string]float64
+		expected []Change
+	}{
+		"NoChanges": {
+			current: map[string]map[string]float64{
+				"read":   {"ops": 1020, "avg": 0.001, "perc99": 0.0102, "count": 1},
+				"insert": {"ops": 1},
+				"update": {"ops": 1},
+			},
+		},
+		"Changes": {
+			current: map[string]map[string]float64{
+				"read": {"ops": 900, "avg": 0.0005, "perc99": 0.02},
+			},
+			expected: []Change{
+				{
+					Test:        "read",
+					Measurement: "avg",
+					Value:       0.0005,
+					Baseline:    Stats{Median: 0.001, N: 5},
+					Score:       -50,
+				},
+				{
+					Test:        "read",
+					Measurement: "ops",
+					Value:       900,
+					Baseline:    Stats{Median: 1000, MAD: 5, N: 5},
+					Score:       -10,
+					Regression:  true,
+				},
+				{
+					Test:        "read",
+					Measurement: "perc99",
+					Value:       0.02,
+					Baseline:    Stats{Median: 0.01, N: 5},
+					Score:       100,
+					Regression:  true,
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := Compare(baselines, tc.current, 3)
+			assert.Len(t, actual, len(tc.expected))
+
+			for i, c := range actual {
+				if i >= len(tc.expected) {
+					break
+				}
+
+				e := tc.expected[i]
+				assert.InDelta(t, e.Score, c.Score, 1e-6)
+
+				c.Score = e.Score
+				assert.Equal(t, e, c)
+			}
+		})
+	}
+}
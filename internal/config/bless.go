@@ -0,0 +1,160 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// Bless returns a copy of expected results updated to match the given actual results.
+//
+// Test names are added to and removed from fail/skip/pass lists only when needed;
+// stats are recomputed.
+// Like for comparison, passed tests with measurements out of bounds are considered failed.
+// Ignored tests and tests with unknown results are left as is.
+// Patterns are kept; exact names are added to override them when needed.
+func (expected *ExpectedResults) Bless(actual map[string]TestResult) (*ExpectedResults, error) {
+	mt, err := expected.newMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	m := mt.names
+
+	tests := slices.Sorted(maps.Keys(actual))
+
+	// Tests are sorted, so a test is always handled before tests that have it as a prefix.
+	for _, test := range tests {
+		status, _ := expected.actualStatus(test, actual[test])
+
+		switch status {
+		case Fail, Skip, Pass:
+		case Ignore, Unknown:
+			fallthrough
+		default:
+			continue
+		}
+
+		s, _, err := mt.lookup(test)
+		if err != nil {
+			return nil, err
+		}
+
+		if s == status || s == Ignore {
+			continue
+		}
+
+		if err = blessName(mt, test, status); err != nil {
+			return nil, err
+		}
+	}
+
+	// prefixes of other tests can't be removed without affecting them
+	prefixes := make(map[string]struct{})
+
+	for _, test := range tests {
+		for prefix := nextPrefix(test); prefix != ""; prefix = nextPrefix(prefix) {
+			prefixes[prefix] = struct{}{}
+		}
+	}
+
+	// remove names that are no longer needed
+	for _, test := range tests {
+		status, ok := m[test]
+		if !ok || status == Ignore {
+			continue
+		}
+
+		if _, ok = prefixes[test]; ok {
+			continue
+		}
+
+		if err = blessName(mt, test, status); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &ExpectedResults{
+		Default: expected.Default,
+		Ignore:  slices.Clone(expected.Ignore),
+		Info:    expected.Info,
+
+		Measurements:  expected.Measurements,
+		PerfTolerance: expected.PerfTolerance,
+	}
+
+	for _, p := range mt.patterns {
+		m[p.entry] = p.status
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(m)) {
+		switch status := m[name]; status {
+		case Fail:
+			res.Fail = append(res.Fail, name)
+		case Skip:
+			res.Skip = append(res.Skip, name)
+		case Pass:
+			res.Pass = append(res.Pass, name)
+		case Ignore, Unknown:
+			fallthrough
+		default:
+			// ignored names and patterns are already copied
+		}
+	}
+
+	// expected errors of entries removed from the fail list are removed too
+	for e, re := range expected.Errors {
+		if !slices.Contains(res.Fail, e) {
+			continue
+		}
+
+		if res.Errors == nil {
+			res.Errors = make(map[string]string)
+		}
+
+		res.Errors[e] = re
+	}
+
+	// the next run does the same comparison, so tests that failed differently are not counted as failed
+	cmp, err := res.Compare(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Stats = &Stats{
+		Failed:  len(cmp.Failed),
+		Skipped: len(cmp.Skipped),
+		Passed:  len(cmp.Passed),
+	}
+
+	return res, nil
+}
+
+// blessName keeps the test name in the matcher only if it is needed for the test to get the given status.
+func blessName(m *matcher, test string, status Status) error {
+	delete(m.names, test)
+
+	s, _, err := m.lookup(test)
+	if err != nil {
+		return err
+	}
+
+	if s != status {
+		m.names[test] = status
+	}
+
+	return nil
+}
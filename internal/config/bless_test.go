@@ -0,0 +1,198 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBless(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Stats: &Stats{
+			Failed: 4,
+			Passed: 2,
+		},
+		Fail: []string{
+			"pkg/TestDumpRestore",
+			"pkg/TestDumpRestore/sample_geospatial",
+			"pkg/TestExportImport",
+			"pkg/TestRemoved",
+		},
+		Pass: []string{
+			"pkg/TestDumpRestore/sample_analytics",
+		},
+		Ignore: []string{
+			"pkg/TestFlaky",
+		},
+	}
+
+	actual := map[string]TestResult{
+		"pkg/TestDumpRestore":                   {Status: Fail},
+		"pkg/TestDumpRestore/sample_analytics":  {Status: Pass},
+		"pkg/TestDumpRestore/sample_geospatial": {Status: Pass},
+		"pkg/TestDumpRestore/sample_mflix":      {Status: Fail},
+		"pkg/TestExportImport":                  {Status: Pass},
+		"pkg/TestFlaky":                         {Status: Fail},
+		"pkg/TestNew":                           {Status: Skip},
+		"pkg/TestUnknown":                       {Status: Unknown},
+	}
+
+	res, err := expected.Bless(actual)
+	require.NoError(t, err)
+
+	expectedBlessed := &ExpectedResults{
+		Default: Pass,
+		Stats: &Stats{
+			Failed:  2,
+			Skipped: 1,
+			Passed:  3,
+		},
+		Fail: []string{
+			"pkg/TestDumpRestore",
+			"pkg/TestRemoved",
+		},
+		Skip: []string{
+			"pkg/TestNew",
+		},
+		Pass: []string{
+			"pkg/TestDumpRestore/sample_analytics",
+			"pkg/TestDumpRestore/sample_geospatial",
+		},
+		Ignore: []string{
+			"pkg/TestFlaky",
+		},
+	}
+	assert.Equal(t, expectedBlessed, res)
+
+	cmp, err := res.Compare(actual)
+	require.NoError(t, err)
+	assert.Empty(t, cmp.XFailed)
+	assert.Empty(t, cmp.XSkipped)
+	assert.Empty(t, cmp.XPassed)
+}
+
+func TestBlessPatterns(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail: []string{
+			"glob:pkg/TestDumpRestore/*",
+			"pkg/TestDumpRestore/sample_mflix",
+		},
+	}
+
+	actual := map[string]TestResult{
+		"pkg/TestDumpRestore":                   {Status: Pass},
+		"pkg/TestDumpRestore/sample_analytics":  {Status: Fail},
+		"pkg/TestDumpRestore/sample_geospatial": {Status: Pass},
+		"pkg/TestDumpRestore/sample_mflix":      {Status: Fail},
+	}
+
+	res, err := expected.Bless(actual)
+	require.NoError(t, err)
+
+	expectedBlessed := &ExpectedResults{
+		Default: Pass,
+		Stats: &Stats{
+			Failed: 2,
+			Passed: 2,
+		},
+		Fail: []string{
+			"glob:pkg/TestDumpRestore/*",
+		},
+		Pass: []string{
+			"pkg/TestDumpRestore/sample_geospatial",
+		},
+	}
+	assert.Equal(t, expectedBlessed, res)
+}
+
+func TestBlessMeasurements(t *testing.T) {
+	t.Parallel()
+
+	minOPS := 2000.0
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Measurements: map[string]map[string]Bound{
+			"read": {"ops": {Min: &minOPS}},
+		},
+	}
+
+	actual := map[string]TestResult{
+		"read":   {Status: Pass, Measurements: map[string]float64{"ops": 1000}},
+		"update": {Status: Pass, Measurements: map[string]float64{"ops": 1000}},
+	}
+
+	res, err := expected.Bless(actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"read"}, res.Fail)
+	assert.Empty(t, res.Pass)
+	assert.Equal(t, &Stats{Failed: 1, Passed: 1}, res.Stats)
+
+	cmp, err := res.Compare(actual)
+	require.NoError(t, err)
+	assert.Equal(t, *res.Stats, cmp.Stats)
+}
+
+func TestBlessErrors(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail:    []string{"plain", "sha1", "sha256"},
+		Errors: map[string]string{
+			"plain":  "Unsupported authentication mechanism",
+			"sha1":   "Unsupported authentication mechanism",
+			"sha256": "Authentication failed",
+		},
+	}
+
+	actual := map[string]TestResult{
+		"plain":  {Status: Fail, Output: "Unsupported authentication mechanism \"PLAIN\""},
+		"sha1":   {Status: Pass},
+		"sha256": {Status: Fail, Output: "connection refused"},
+	}
+
+	res, err := expected.Bless(actual)
+	require.NoError(t, err)
+
+	expectedBlessed := &ExpectedResults{
+		Default: Pass,
+		Stats: &Stats{
+			Failed: 1,
+			Passed: 1,
+		},
+		Fail: []string{"plain", "sha256"},
+		Errors: map[string]string{
+			"plain":  "Unsupported authentication mechanism",
+			"sha256": "Authentication failed",
+		},
+	}
+	assert.Equal(t, expectedBlessed, res)
+
+	// expected errors are not changed, so the next run still fails
+	cmp, err := res.Compare(actual)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Failed: 1, Passed: 1, FailedDifferently: 1}, cmp.Stats)
+}
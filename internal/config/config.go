@@ -15,6 +15,8 @@
 // Package config provides project configuration.
 package config
 
+import "time"
+
 // Status represents the status of a single test.
 type Status string
 
@@ -34,4 +36,7 @@ type Config struct {
 	Runner  RunnerType
 	Params  RunnerParams
 	Results *ExpectedResults
+	Serial  bool          // do not run in parallel with other configurations sharing the same directory
+	Retries int           // number of retries for tests with unexpected results
+	Timeout time.Duration // if set, bounds the whole run, including retries
 }
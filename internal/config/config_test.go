@@ -15,6 +15,9 @@
 package config
 
 import (
+	"maps"
+	"regexp"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,3 +54,215 @@ func FuzzNextPrefix(f *testing.F) {
 		}
 	})
 }
+
+func TestCompareFlaky(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail:    []string{"pkg/TestFail"},
+	}
+
+	actual := map[string]TestResult{
+		"pkg/TestFail":   {Status: Fail, Attempts: 2},
+		"pkg/TestFlaky":  {Status: Pass, Attempts: 3},
+		"pkg/TestPass":   {Status: Pass},
+		"pkg/TestFailed": {Status: Fail, Attempts: 3},
+	}
+
+	cmp, err := expected.Compare(actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pkg/TestFail", "pkg/TestFlaky"}, slices.Sorted(maps.Keys(cmp.Flaky)))
+	assert.Equal(t, []string{"pkg/TestFlaky", "pkg/TestPass"}, slices.Sorted(maps.Keys(cmp.Passed)))
+	assert.Equal(t, []string{"pkg/TestFailed"}, slices.Sorted(maps.Keys(cmp.XFailed)))
+	assert.Equal(t, Stats{Failed: 1, Passed: 2, XFailed: 1, Flaky: 2}, cmp.Stats)
+}
+
+func TestExpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail:    []string{"pkg/TestA"},
+		Skip:    []string{"pkg/TestA/Sub"},
+		Ignore:  []string{"pkg/TestB"},
+	}
+
+	for test, status := range map[string]Status{
+		"pkg/TestA":        Fail,
+		"pkg/TestA/Other":  Fail,
+		"pkg/TestA/Sub":    Skip,
+		"pkg/TestA/Sub/1":  Skip,
+		"pkg/TestB":        Ignore,
+		"pkg/TestC":        Pass,
+		"pkg/TestAnything": Pass,
+	} {
+		actual, err := expected.Status(test)
+		require.NoError(t, err)
+		assert.Equal(t, status, actual, "test = %s", test)
+	}
+}
+
+func TestCompareStale(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail: []string{
+			"pkg/TestDumpRestore",
+			"pkg/TestDumpRestore/sample_geospatial",
+			"pkg/TestRenamed",
+		},
+		Skip:   []string{"glob:pkg/TestExport/*", "re:pkg/TestImport/.+"},
+		Pass:   []string{"pkg/"},
+		Ignore: []string{"pkg/TestRemoved"},
+		Measurements: map[string]map[string]Bound{
+			"pkg/TestImport":  {"ops": {}},
+			"pkg/TestBench":   {"ops": {}},
+			"pkg/TestRenamed": {"ops": {}},
+		},
+	}
+
+	actual := map[string]TestResult{
+		"pkg/TestDumpRestore/sample_mflix": {Status: Fail},
+		"pkg/TestExport/json":              {Status: Skip},
+		"pkg/TestImport":                   {Status: Pass},
+	}
+
+	cmp, err := expected.Compare(actual)
+	require.NoError(t, err)
+
+	expectedStale := map[string]Status{
+		"pkg/TestDumpRestore/sample_geospatial": Fail,
+		"pkg/TestRenamed":                       Fail,
+		"re:pkg/TestImport/.+":                  Skip,
+		"pkg/TestRemoved":                       Ignore,
+		"pkg/TestBench":                         Pass,
+	}
+	assert.Equal(t, expectedStale, cmp.Stale)
+
+	// stale entries are not known for partial runs
+	expected.Filter, err = NewFilter("TestExport", "")
+	require.NoError(t, err)
+
+	cmp, err = expected.Compare(actual)
+	require.NoError(t, err)
+	assert.Empty(t, cmp.Stale)
+}
+
+func TestCompareIgnored(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Ignore:  []string{"pkg/TestFlaky"},
+	}
+
+	actual := map[string]TestResult{
+		"pkg/TestFlaky/1": {Status: Pass},
+		"pkg/TestFlaky/2": {Status: Pass},
+		"pkg/TestFlaky/3": {Status: Fail, Output: "timeout"},
+		"pkg/TestFlaky/4": {Status: Unknown},
+		"pkg/TestPass":    {Status: Pass},
+	}
+
+	cmp, err := expected.Compare(actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pkg/TestPass"}, slices.Sorted(maps.Keys(cmp.Passed)))
+	assert.Equal(t, TestResult{Status: Fail, Output: "timeout"}, cmp.Ignored["pkg/TestFlaky/3"])
+	assert.Len(t, cmp.Ignored, 4)
+	assert.Empty(t, cmp.Unknown)
+	assert.Equal(t, Stats{Passed: 1, IgnoredFailed: 1, IgnoredPassed: 2}, cmp.Stats)
+}
+
+func TestCompareFailedDifferently(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail:    []string{"pkg/TestAuth", "glob:pkg/TestQuery/*"},
+		Errors: map[string]string{
+			"pkg/TestAuth":         `Authentication failed`,
+			"glob:pkg/TestQuery/*": `unknown operator: \$\w+`,
+		},
+	}
+
+	actual := map[string]TestResult{
+		"pkg/TestAuth/sha1":    {Status: Fail, Output: "=== RUN\nError: Authentication failed\n--- FAIL"},
+		"pkg/TestAuth/sha256":  {Status: Fail, Output: "=== RUN\npanic: nil pointer dereference\n--- FAIL"},
+		"pkg/TestAuth/plain":   {Status: Pass},
+		"pkg/TestQuery/regex":  {Status: Fail, Output: "unknown operator: $regex"},
+		"pkg/TestQuery/expr":   {Status: Fail, Output: "line 1\nline 2\nline 3\nline 4\nline 5\nline 6", Attempts: 2},
+		"pkg/TestQuery/simple": {Status: Skip},
+	}
+
+	cmp, err := expected.Compare(actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pkg/TestAuth/sha1", "pkg/TestQuery/regex"}, slices.Sorted(maps.Keys(cmp.Failed)))
+	assert.Equal(t, []string{"pkg/TestAuth/plain"}, slices.Sorted(maps.Keys(cmp.XPassed)))
+	assert.Equal(t, []string{"pkg/TestQuery/simple"}, slices.Sorted(maps.Keys(cmp.XSkipped)))
+	assert.Empty(t, cmp.Flaky)
+
+	expectedDifferently := map[string]string{
+		"pkg/TestAuth/sha256": "Expected error: Authentication failed\n\t" +
+			"Actual error:\n\tpanic: nil pointer dereference\n\t--- FAIL\n\t\n\t" +
+			"=== RUN\n\tpanic: nil pointer dereference\n\t--- FAIL",
+		"pkg/TestQuery/expr": "Expected error: unknown operator: \\$\\w+\n\t" +
+			"Actual error:\n\tline 2\n\tline 3\n\tline 4\n\tline 5\n\tline 6\n\t\n\t" +
+			"line 1\n\tline 2\n\tline 3\n\tline 4\n\tline 5\n\tline 6",
+	}
+
+	differently := make(map[string]string)
+	for test, tr := range cmp.FailedDifferently {
+		differently[test] = tr.Output
+	}
+
+	assert.Equal(t, expectedDifferently, differently)
+	assert.Equal(t, Stats{Failed: 2, XSkipped: 1, XPassed: 1, FailedDifferently: 2}, cmp.Stats)
+
+	expected.Errors = map[string]string{"pkg/TestOther": "error"}
+	_, err = expected.Compare(actual)
+	require.EqualError(t, err, `expected error is set for "pkg/TestOther" that is not in the fail list`)
+}
+
+func TestCompareTimedOut(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail:    []string{"slow", "broken"},
+	}
+
+	actual := map[string]TestResult{
+		"slow":   {Status: Fail, Output: "not run: configuration timed out after 1s", TimedOut: true},
+		"broken": {Status: Fail, Output: "error"},
+		"fast":   {Status: Fail, Output: "test timed out after 1s", TimedOut: true},
+	}
+
+	cmp, err := expected.Compare(actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"broken"}, slices.Sorted(maps.Keys(cmp.Failed)))
+	assert.Equal(t, []string{"slow"}, slices.Sorted(maps.Keys(cmp.FailedDifferently)))
+	assert.Equal(t, []string{"fast"}, slices.Sorted(maps.Keys(cmp.XFailed)))
+	assert.True(t, cmp.FailedDifferently["slow"].TimedOut)
+}
+
+func TestExpectedNames(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default:      Pass,
+		Fail:         []string{"update", "glob:scan*"},
+		Skip:         []string{"re:^insert$", "delete"},
+		Pass:         []string{"read"},
+		Measurements: map[string]map[string]Bound{"read": {}, "scan": {}},
+	}
+	assert.Equal(t, []string{"delete", "read", "scan", "update"}, expected.Names())
+
+	expected.Filter = &Filter{Skip: regexp.MustCompile(`^s`)}
+	assert.Equal(t, []string{"delete", "read", "update"}, expected.Names())
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// DiffEntry represents a single test with different statuses in two runs.
+//
+// Missing status means that the test was not run.
+type DiffEntry struct {
+	Test   string
+	Base   *TestResult
+	Target *TestResult
+}
+
+// BaseStatus returns the status of the test in the base run, or "missing" if it was not run there.
+func (de *DiffEntry) BaseStatus() string {
+	return statusOrMissing(de.Base)
+}
+
+// TargetStatus returns the status of the test in the target run, or "missing" if it was not run there.
+func (de *DiffEntry) TargetStatus() string {
+	return statusOrMissing(de.Target)
+}
+
+// Transition returns "base -> target" statuses transition of the entry.
+func (de *DiffEntry) Transition() string {
+	return de.BaseStatus() + " -> " + de.TargetStatus()
+}
+
+// statusOrMissing returns the status of the given result or "missing" for nil.
+func statusOrMissing(tr *TestResult) string {
+	if tr == nil {
+		return "missing"
+	}
+
+	return string(tr.Status)
+}
+
+// DiffResults represents differences between actual results of two runs.
+type DiffResults struct {
+	Same    int         // number of tests with the same status
+	Entries []DiffEntry // sorted by test name
+}
+
+// Transitions returns the number of entries by "base -> target" statuses transition.
+func (dr *DiffResults) Transitions() map[string]int {
+	res := make(map[string]int)
+
+	for _, e := range dr.Entries {
+		res[e.Transition()]++
+	}
+
+	return res
+}
+
+// Diff compares actual results of two runs, regardless of expected results.
+func Diff(base, target map[string]TestResult) *DiffResults {
+	tests := slices.Sorted(maps.Keys(base))
+
+	for test := range target {
+		if _, ok := base[test]; !ok {
+			tests = append(tests, test)
+		}
+	}
+
+	slices.Sort(tests)
+
+	res := new(DiffResults)
+
+	for _, test := range tests {
+		b, bok := base[test]
+		t, tok := target[test]
+
+		if bok && tok && b.Status == t.Status {
+			res.Same++
+			continue
+		}
+
+		e := DiffEntry{Test: test}
+
+		if bok {
+			e.Base = &b
+		}
+
+		if tok {
+			e.Target = &t
+		}
+
+		res.Entries = append(res.Entries, e)
+	}
+
+	return res
+}
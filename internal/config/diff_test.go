@@ -0,0 +1,62 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	base := map[string]TestResult{
+		"pkg/TestA":       {Status: Pass},
+		"pkg/TestB":       {Status: Pass},
+		"pkg/TestC":       {Status: Fail, Output: "base"},
+		"pkg/TestBase":    {Status: Skip},
+		"pkg/TestUnknown": {Status: Pass},
+	}
+
+	target := map[string]TestResult{
+		"pkg/TestA":       {Status: Pass},
+		"pkg/TestB":       {Status: Fail, Output: "target"},
+		"pkg/TestC":       {Status: Fail},
+		"pkg/TestTarget":  {Status: Pass},
+		"pkg/TestUnknown": {Status: Unknown},
+	}
+
+	res := Diff(base, target)
+
+	expected := &DiffResults{
+		Same: 2,
+		Entries: []DiffEntry{
+			{Test: "pkg/TestB", Base: &TestResult{Status: Pass}, Target: &TestResult{Status: Fail, Output: "target"}},
+			{Test: "pkg/TestBase", Base: &TestResult{Status: Skip}},
+			{Test: "pkg/TestTarget", Target: &TestResult{Status: Pass}},
+			{Test: "pkg/TestUnknown", Base: &TestResult{Status: Pass}, Target: &TestResult{Status: Unknown}},
+		},
+	}
+	assert.Equal(t, expected, res)
+
+	expectedTransitions := map[string]int{
+		"pass -> fail":    1,
+		"skip -> missing": 1,
+		"missing -> pass": 1,
+		"pass -> unknown": 1,
+	}
+	assert.Equal(t, expectedTransitions, res.Transitions())
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Filter selects tests to run by their full names.
+//
+//nolint:vet // for readability
+type Filter struct {
+	Run  *regexp.Regexp // if set, only matching tests are run
+	Skip *regexp.Regexp // if set, matching tests are not run
+
+	// if set, tests were selected by the runner itself with its own rules for names
+	// (for example, `go test` matches names without the package path), so all results are accepted
+	ByRunner bool
+}
+
+// NewFilter returns a new filter for the given regular expressions, or nil if both are empty.
+func NewFilter(run, skip string) (*Filter, error) {
+	if run == "" && skip == "" {
+		return nil, nil
+	}
+
+	var f Filter
+	var err error
+
+	if run != "" {
+		if f.Run, err = regexp.Compile(run); err != nil {
+			return nil, fmt.Errorf("invalid run filter: %w", err)
+		}
+	}
+
+	if skip != "" {
+		if f.Skip, err = regexp.Compile(skip); err != nil {
+			return nil, fmt.Errorf("invalid skip filter: %w", err)
+		}
+	}
+
+	return &f, nil
+}
+
+// Match returns true if the test with the given name should be run.
+// Nil filter matches all tests.
+func (f *Filter) Match(test string) bool {
+	if f == nil || f.ByRunner {
+		return true
+	}
+
+	if f.Run != nil && !f.Run.MatchString(test) {
+		return false
+	}
+
+	if f.Skip != nil && f.Skip.MatchString(test) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFilter("", "")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+	assert.True(t, f.Match("pkg/TestDump"))
+
+	_, err = NewFilter("(", "")
+	require.EqualError(t, err, "invalid run filter: error parsing regexp: missing closing ): `(`")
+
+	f, err = NewFilter("TestDump", "/sample_mflix$")
+	require.NoError(t, err)
+
+	for test, expected := range map[string]bool{
+		"pkg/TestDump":                   true,
+		"pkg/TestDumpRestore":            true,
+		"pkg/TestDump/sample_analytics":  true,
+		"pkg/TestDump/sample_mflix":      false,
+		"pkg/TestExportImport":           false,
+		"pkg/TestExportImport/TestDump/": true,
+	} {
+		assert.Equal(t, expected, f.Match(test), "test = %s", test)
+	}
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Stats: &Stats{
+			Failed: 1,
+			Passed: 2,
+		},
+		Fail:   []string{"pkg/TestExportImport"},
+		Filter: f,
+	}
+
+	actual := map[string]TestResult{
+		"pkg/TestDump":              {Status: Pass},
+		"pkg/TestDump/sample_mflix": {Status: Fail},
+		"pkg/TestExportImport":      {Status: Pass},
+	}
+
+	cmp, err := expected.Compare(actual)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Passed: 1}, cmp.Stats)
+
+	// go test was run with "-run=^TestDump$", so names with the package path do not match
+	f, err = NewFilter("^TestDump$", "")
+	require.NoError(t, err)
+	assert.False(t, f.Match("pkg/TestDump"))
+
+	f.ByRunner = true
+	assert.True(t, f.Match("pkg/TestDump"))
+
+	expected.Filter = f
+
+	cmp, err = expected.Compare(map[string]TestResult{"pkg/TestDump": {Status: Pass}})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Passed: 1}, cmp.Stats)
+}
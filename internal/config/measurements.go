@@ -0,0 +1,80 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Bound represents expected bounds of a single measurement; nil bounds are not checked.
+type Bound struct {
+	Min *float64
+	Max *float64
+}
+
+// check returns a description of the bound violation by the given value, or an empty string.
+// Bounds are loosened by the given percentage.
+func (b Bound) check(name string, v, tolerance float64) string {
+	if b.Min != nil {
+		if limit := *b.Min * (1 - tolerance/100); v < limit {
+			return fmt.Sprintf("%s = %g is less than %g", name, v, limit)
+		}
+	}
+
+	if b.Max != nil {
+		if limit := *b.Max * (1 + tolerance/100); v > limit {
+			return fmt.Sprintf("%s = %g is greater than %g", name, v, limit)
+		}
+	}
+
+	return ""
+}
+
+// actualStatus returns the status of the given actual result of the test:
+// passed tests with measurements out of bounds are failed, with descriptions of bound violations.
+func (expected *ExpectedResults) actualStatus(test string, tr TestResult) (Status, []string) {
+	if tr.Status != Pass {
+		return tr.Status, nil
+	}
+
+	if violations := expected.checkMeasurements(test, tr.Measurements); len(violations) > 0 {
+		return Fail, violations
+	}
+
+	return Pass, nil
+}
+
+// checkMeasurements returns descriptions of bound violations by measurements of the given test.
+func (expected *ExpectedResults) checkMeasurements(test string, ms map[string]float64) []string {
+	bounds := expected.Measurements[test]
+
+	var res []string
+
+	for _, name := range slices.Sorted(maps.Keys(bounds)) {
+		v, ok := ms[name]
+		if !ok {
+			res = append(res, fmt.Sprintf("%s is missing", name))
+			continue
+		}
+
+		if s := bounds[name].check(name, v, expected.PerfTolerance); s != "" {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
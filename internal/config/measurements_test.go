@@ -0,0 +1,82 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareMeasurements(t *testing.T) {
+	t.Parallel()
+
+	minOPS, maxPerc99 := 2000.0, 0.05
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Measurements: map[string]map[string]Bound{
+			"read":   {"ops": {Min: &minOPS}, "perc99": {Max: &maxPerc99}},
+			"update": {"ops": {Min: &minOPS}},
+			"insert": {"ops": {Min: &minOPS}},
+		},
+	}
+
+	actual := map[string]TestResult{
+		"read":   {Status: Pass, Measurements: map[string]float64{"ops": 1900, "perc99": 0.054}},
+		"update": {Status: Pass, Measurements: map[string]float64{"ops": 2500}},
+		"insert": {Status: Pass, Measurements: map[string]float64{"count": 100}},
+		"scan":   {Status: Pass, Measurements: map[string]float64{"ops": 1}},
+	}
+
+	for name, tc := range map[string]struct {
+		tolerance float64
+		xfailed   map[string]string // test name -> output
+	}{
+		"Strict": {
+			xfailed: map[string]string{
+				"read":   "Measurements out of bounds:\n\tops = 1900 is less than 2000\n\tperc99 = 0.054 is greater than 0.05",
+				"insert": "Measurements out of bounds:\n\tops is missing",
+			},
+		},
+		"Tolerance": {
+			tolerance: 10,
+			xfailed: map[string]string{
+				"insert": "Measurements out of bounds:\n\tops is missing",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			e := *expected
+			e.PerfTolerance = tc.tolerance
+
+			cmp, err := e.Compare(actual)
+			require.NoError(t, err)
+
+			xfailed := make(map[string]string)
+			for test, tr := range cmp.XFailed {
+				assert.Equal(t, Fail, tr.Status)
+				assert.Equal(t, actual[test].Measurements, tr.Measurements)
+				xfailed[test] = tr.Output
+			}
+
+			assert.Equal(t, tc.xfailed, xfailed)
+			assert.Equal(t, len(actual)-len(tc.xfailed), cmp.Stats.Passed)
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+)
+
+// Prefixes of pattern entries in expected results.
+// Other entries are test names or prefixes, even if they contain characters like `*` or `[`.
+const (
+	globPrefix  = "glob:"
+	regexPrefix = "re:"
+)
+
+// IsPattern returns true if the given entry of expected results is a glob or regular expression pattern
+// instead of a test name or prefix.
+func IsPattern(entry string) bool {
+	return strings.HasPrefix(entry, globPrefix) || strings.HasPrefix(entry, regexPrefix)
+}
+
+// pattern represents a glob or regular expression entry of expected results.
+type pattern struct {
+	entry   string
+	re      *regexp.Regexp // matches the whole test name
+	literal int            // number of literal characters; more is more specific
+	status  Status
+}
+
+// parsePattern parses a glob or regular expression entry.
+//
+// In globs, `*` matches any sequence of characters including `/`, `?` matches any single character,
+// and `[...]` matches a character class (`[!...]` is negated); `\` escapes the next character.
+func parsePattern(entry string, status Status) (*pattern, error) {
+	expr, isRegex := strings.CutPrefix(entry, regexPrefix)
+	if !isRegex {
+		var err error
+		if expr, err = globToRegex(strings.TrimPrefix(entry, globPrefix)); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", entry, err)
+		}
+	}
+
+	re, err := regexp.Compile(`^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", entry, err)
+	}
+
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", entry, err)
+	}
+
+	return &pattern{
+		entry:   entry,
+		re:      re,
+		literal: literalLen(parsed.Simplify()),
+		status:  status,
+	}, nil
+}
+
+// globToRegex converts glob to regular expression.
+func globToRegex(glob string) (string, error) {
+	var res strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			res.WriteString(`.*`)
+
+		case '?':
+			res.WriteString(`.`)
+
+		case '[':
+			j := strings.IndexByte(glob[i+1:], ']')
+			if j < 0 {
+				return "", fmt.Errorf("unclosed character class")
+			}
+
+			class := glob[i+1 : i+1+j]
+			if c, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + c
+			}
+
+			res.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += j + 1
+
+		case '\\':
+			if i+1 == len(glob) {
+				return "", fmt.Errorf("trailing backslash")
+			}
+
+			i++
+			res.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+
+		default:
+			res.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return res.String(), nil
+}
+
+// literalLen returns the number of literal characters that any match of the given expression contains.
+func literalLen(re *syntax.Regexp) int {
+	switch re.Op { //nolint:exhaustive // other operators do not guarantee literal characters
+	case syntax.OpLiteral:
+		return len(re.Rune)
+
+	case syntax.OpConcat, syntax.OpCapture:
+		var res int
+		for _, sub := range re.Sub {
+			res += literalLen(sub)
+		}
+
+		return res
+
+	case syntax.OpPlus:
+		return literalLen(re.Sub[0])
+
+	default:
+		return 0
+	}
+}
+
+// matcher finds expected statuses of tests using names, prefixes, and patterns of expected results.
+type matcher struct {
+	def      Status
+	names    map[string]Status // exact names and prefixes
+	patterns []*pattern
+	errors   map[string]*regexp.Regexp // expected errors by entry
+}
+
+// newMatcher returns a new matcher for expected results.
+func (expected *ExpectedResults) newMatcher() (*matcher, error) {
+	res := &matcher{
+		def:    expected.Default,
+		names:  make(map[string]Status),
+		errors: make(map[string]*regexp.Regexp, len(expected.Errors)),
+	}
+
+	for e, expr := range expected.Errors {
+		if !slices.Contains(expected.Fail, e) {
+			return nil, fmt.Errorf("expected error is set for %q that is not in the fail list", e)
+		}
+
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected error of %q: %w", e, err)
+		}
+
+		res.errors[e] = re
+	}
+
+	for _, g := range []struct {
+		status  Status
+		entries []string
+	}{
+		{Fail, expected.Fail},
+		{Skip, expected.Skip},
+		{Pass, expected.Pass},
+		{Ignore, expected.Ignore},
+	} {
+		for _, e := range g.entries {
+			if !IsPattern(e) {
+				res.names[e] = g.status
+				continue
+			}
+
+			p, err := parsePattern(e, g.status)
+			if err != nil {
+				return nil, err
+			}
+
+			res.patterns = append(res.patterns, p)
+		}
+	}
+
+	return res, nil
+}
+
+// lookup returns expected status for the given test name and the matched entry ("" for the default status).
+//
+// The most specific entry wins: the longest exact name or prefix, or the pattern with the most literal characters.
+// Names and prefixes win over patterns with the same number of literal characters.
+// It is an error if patterns with the same number of literal characters give different statuses.
+func (m *matcher) lookup(test string) (Status, string, error) {
+	res, entry, nameLen := m.def, "", -1
+
+	for prefix := test; prefix != ""; prefix = nextPrefix(prefix) {
+		if s, ok := m.names[prefix]; ok {
+			res, entry, nameLen = s, prefix, len(prefix)
+			break
+		}
+	}
+
+	// the most specific matching patterns
+	var matched []*pattern
+
+	for _, p := range m.patterns {
+		if p.literal <= nameLen || !p.re.MatchString(test) {
+			continue
+		}
+
+		switch {
+		case len(matched) == 0 || p.literal > matched[0].literal:
+			matched = []*pattern{p}
+		case p.literal == matched[0].literal:
+			matched = append(matched, p)
+		}
+	}
+
+	if len(matched) == 0 {
+		return res, entry, nil
+	}
+
+	for _, p := range matched[1:] {
+		if p.status != matched[0].status {
+			return "", "", fmt.Errorf(
+				"test %q matches patterns %q (%s) and %q (%s) with the same specificity",
+				test, matched[0].entry, matched[0].status, p.entry, p.status,
+			)
+		}
+	}
+
+	return matched[0].status, matched[0].entry, nil
+}
+
+// stale returns entries that match none of the given test names, with their statuses.
+func (m *matcher) stale(tests []string) map[string]Status {
+	// all names and prefixes of tests
+	prefixes := make(map[string]struct{}, len(tests))
+
+	for _, test := range tests {
+		for prefix := test; prefix != ""; prefix = nextPrefix(prefix) {
+			if _, ok := prefixes[prefix]; ok {
+				break
+			}
+
+			prefixes[prefix] = struct{}{}
+		}
+	}
+
+	res := make(map[string]Status)
+
+	for e, s := range m.names {
+		if _, ok := prefixes[e]; !ok {
+			res[e] = s
+		}
+	}
+
+	for _, p := range m.patterns {
+		if !slices.ContainsFunc(tests, p.re.MatchString) {
+			res[p.entry] = p.status
+		}
+	}
+
+	return res
+}
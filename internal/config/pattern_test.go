@@ -0,0 +1,152 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePattern(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		entry   string
+		literal int
+		match   []string
+		noMatch []string
+		err     string
+	}{
+		"GlobStar": {
+			entry:   "glob:*_secured",
+			literal: 8,
+			match:   []string{"pkg/TestAuth/sha1_secured", "_secured"},
+			noMatch: []string{"pkg/TestAuth/sha1_secured/sub", "pkg/TestAuth/sha1"},
+		},
+		"GlobSuffix": {
+			entry:   "glob:pkg/TestDumpRestore/sample_geo*",
+			literal: 30,
+			match:   []string{"pkg/TestDumpRestore/sample_geospatial", "pkg/TestDumpRestore/sample_geo/sub"},
+			noMatch: []string{"pkg/TestDumpRestore/sample_mflix", "other/pkg/TestDumpRestore/sample_geo"},
+		},
+		"GlobClass": {
+			entry:   "glob:test_[!ab]?.py",
+			literal: 8,
+			match:   []string{"test_c1.py"},
+			noMatch: []string{"test_a1.py", "test_c.py", "test_c1xpy"},
+		},
+		"GlobEscape": {
+			entry:   `glob:test\[1\]*`,
+			literal: 7,
+			match:   []string{"test[1]", "test[1]-2"},
+			noMatch: []string{"test1"},
+		},
+		"GlobUnclosed": {
+			entry: "glob:test[1",
+			err:   `invalid glob "glob:test[1": unclosed character class`,
+		},
+		"Regex": {
+			entry:   `re:pkg/Test(Find|Aggregate)/.+_v\d+`,
+			literal: 11,
+			match:   []string{"pkg/TestFind/a_v1", "pkg/TestAggregate/b/c_v22"},
+			noMatch: []string{"pkg/TestFind/a_v", "xpkg/TestFind/a_v1"},
+		},
+		"RegexInvalid": {
+			entry: "re:(",
+			err:   "invalid pattern \"re:(\": error parsing regexp: missing closing ): `^(?:()$`",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.True(t, IsPattern(tc.entry))
+
+			p, err := parsePattern(tc.entry, Fail)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.literal, p.literal)
+
+			for _, test := range tc.match {
+				assert.True(t, p.re.MatchString(test), "test = %s", test)
+			}
+
+			for _, test := range tc.noMatch {
+				assert.False(t, p.re.MatchString(test), "test = %s", test)
+			}
+		})
+	}
+
+	assert.False(t, IsPattern("pkg/TestDumpRestore/sample_geospatial"))
+
+	// names like pytest parametrized ids are not patterns without the prefix
+	assert.False(t, IsPattern("test_x[1]"))
+	assert.False(t, IsPattern("test_*"))
+}
+
+func TestCompareWithPatterns(t *testing.T) {
+	t.Parallel()
+
+	expected := &ExpectedResults{
+		Default: Pass,
+		Fail: []string{
+			"pkg/TestDumpRestore",
+			"glob:*_secured",
+			"test_x[1]",
+		},
+		Pass: []string{
+			"glob:pkg/TestDumpRestore/sample_geo*",
+			"pkg/TestAuth/plain_secured",
+		},
+		Skip: []string{
+			`re:pkg/TestAuth/.*_v\d`,
+		},
+	}
+
+	for test, status := range map[string]Status{
+		"pkg/TestDumpRestore":                   Fail,
+		"pkg/TestDumpRestore/sample_mflix":      Fail, // prefix
+		"pkg/TestDumpRestore/sample_geospatial": Pass, // pattern is longer than prefix
+		"pkg/TestDumpRestore/sample_geo/a_v1":   Pass, // the most literal characters
+		"pkg/TestAuth/sha1_secured":             Fail,
+		"pkg/TestAuth/plain_secured":            Pass, // exact name
+		"pkg/TestAuth/sha1_v1":                  Skip,
+		"pkg/TestOther":                         Pass, // default
+		"test_x[1]":                             Fail, // exact name with brackets
+		"test_x1":                               Pass,
+	} {
+		actual, err := expected.Status(test)
+		require.NoError(t, err)
+		assert.Equal(t, status, actual, "test = %s", test)
+	}
+
+	// both patterns have 15 literal characters
+	expected.Pass = append(expected.Pass, "glob:pkg/TestAuth/?*_v?")
+
+	_, err := expected.Compare(map[string]TestResult{"pkg/TestAuth/sha1": {Status: Pass}})
+	require.NoError(t, err)
+
+	_, err = expected.Compare(map[string]TestResult{"pkg/TestAuth/sha1_v1": {Status: Skip}})
+	require.EqualError(
+		t, err,
+		`test "pkg/TestAuth/sha1_v1" matches patterns "re:pkg/TestAuth/.*_v\\d" (skip) and "glob:pkg/TestAuth/?*_v?" (pass) `+
+			"with the same specificity",
+	)
+}
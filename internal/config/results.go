@@ -17,6 +17,7 @@ package config
 import (
 	"fmt"
 	"maps"
+	"regexp"
 	"slices"
 	"strings"
 )
@@ -28,6 +29,8 @@ type TestResult struct {
 	Status       Status
 	Output       string
 	Measurements map[string]float64
+	Attempts     int  // number of runs if the test was retried
+	TimedOut     bool // the test failed because it was running for too long
 }
 
 // IndentedOutput returns the output of a test result with indented lines.
@@ -47,43 +50,114 @@ type CompareResults struct {
 	XSkipped map[string]TestResult
 	XPassed  map[string]TestResult
 
+	// expected to fail, failed, but output does not match the expected error
+	FailedDifferently map[string]TestResult
+
 	Unknown map[string]TestResult
 
+	// expected results after retries; those tests are also present in expected maps above
+	Flaky map[string]TestResult
+
+	// tests with ignored results, with their actual statuses
+	Ignored map[string]TestResult
+
+	// information of expected results entries by test name, for tests matched by entries that have it
+	Info map[string]EntryInfo
+
+	// entries of expected results (including tests of measurements bounds) that matched no tests,
+	// with their statuses; not set for partial runs
+	Stale map[string]Status
+
 	Stats Stats
 }
 
+// EntryInfo represents optional information about an entry of expected results.
+type EntryInfo struct {
+	Reason string // why the test has that status
+	Issue  string // issue URL
+}
+
+// String returns the reason and issue URL.
+func (ei EntryInfo) String() string {
+	switch {
+	case ei.Issue == "":
+		return ei.Reason
+	case ei.Reason == "":
+		return ei.Issue
+	default:
+		return ei.Reason + " (" + ei.Issue + ")"
+	}
+}
+
 // ExpectedResults represents expected results for specific database.
 type ExpectedResults struct {
 	Default Status
 	Stats   *Stats
 
-	// test names
+	// test names, prefixes, and patterns (see [IsPattern])
 	Fail   []string
 	Skip   []string
 	Pass   []string
 	Ignore []string
+
+	// optional information by entry
+	Info map[string]EntryInfo
+
+	// regular expressions that outputs of failed tests must match, by entry of the Fail list
+	Errors map[string]string
+
+	// bounds of measurements by test name and measurement name;
+	// passed tests with measurements out of bounds are failed
+	Measurements map[string]map[string]Bound
+
+	// percentage by which bounds of measurements are loosened
+	PerfTolerance float64
+
+	// if set, results of other tests and expectations for them are ignored;
+	// used for partial runs
+	Filter *Filter
+}
+
+// Status returns expected status for the given test name, including [Ignore].
+func (expected *ExpectedResults) Status(test string) (Status, error) {
+	m, err := expected.newMatcher()
+	if err != nil {
+		return "", err
+	}
+
+	res, _, err := m.lookup(test)
+
+	return res, err
 }
 
-func (expected *ExpectedResults) mapStatuses() map[string]Status {
-	res := make(map[string]Status)
-
-	for _, g := range []struct {
-		status Status
-		names  []string
-	}{
-		{Fail, expected.Fail},
-		{Skip, expected.Skip},
-		{Pass, expected.Pass},
-		{Ignore, expected.Ignore},
-	} {
-		for _, n := range g.names {
-			res[n] = g.status
+// Names returns sorted names of tests selected by the filter from entries that are not patterns
+// and from bounds of measurements.
+//
+// Entries could be prefixes of test names, so they are only a guess for runners that can't list tests.
+func (expected *ExpectedResults) Names() []string {
+	var res []string
+
+	measurements := slices.Collect(maps.Keys(expected.Measurements))
+
+	for _, names := range [][]string{expected.Fail, expected.Skip, expected.Pass, measurements} {
+		for _, name := range names {
+			if !IsPattern(name) && expected.Filter.Match(name) && !slices.Contains(res, name) {
+				res = append(res, name)
+			}
 		}
 	}
 
+	slices.Sort(res)
+
 	return res
 }
 
+// Validate returns an error if patterns of expected results are invalid.
+func (expected *ExpectedResults) Validate() error {
+	_, err := expected.newMatcher()
+	return err
+}
+
 // Compare compares expected and actual results.
 func (expected *ExpectedResults) Compare(actual map[string]TestResult) (*CompareResults, error) {
 	res := &CompareResults{
@@ -93,22 +167,57 @@ func (expected *ExpectedResults) Compare(actual map[string]TestResult) (*Compare
 		XFailed:  make(map[string]TestResult),
 		XSkipped: make(map[string]TestResult),
 		XPassed:  make(map[string]TestResult),
-		Unknown:  make(map[string]TestResult),
+
+		FailedDifferently: make(map[string]TestResult),
+
+		Unknown: make(map[string]TestResult),
+		Flaky:   make(map[string]TestResult),
+		Ignored: make(map[string]TestResult),
+		Info:    make(map[string]EntryInfo),
+		Stale:   make(map[string]Status),
 	}
 
 	tests := slices.Sorted(maps.Keys(actual))
 
-	m := expected.mapStatuses()
+	m, err := expected.newMatcher()
+	if err != nil {
+		return nil, err
+	}
 
 	for _, test := range tests {
+		if !expected.Filter.Match(test) {
+			continue
+		}
+
 		actualResult := actual[test]
 
-		expectedStatus := expected.Default
+		expectedStatus, entry, err := m.lookup(test)
+		if err != nil {
+			return nil, err
+		}
+
+		if info, ok := expected.Info[entry]; ok {
+			res.Info[test] = info
+		}
+
+		if status, violations := expected.actualStatus(test, actualResult); len(violations) > 0 {
+			actualResult.Status = status
+			actualResult.Output = strings.TrimLeft(
+				actualResult.Output+"\nMeasurements out of bounds:\n"+strings.Join(violations, "\n"), "\n",
+			)
+		}
+
+		// the test failed for the wrong reason; timeouts are never expected failures
+		var differently bool
+
+		if expectedStatus == Fail && actualResult.Status == Fail {
+			if re := m.errors[entry]; re != nil && !re.MatchString(actualResult.Output) {
+				differently = true
+				actualResult.Output = failedDifferentlyOutput(re, actualResult.Output)
+			}
 
-		for prefix := test; prefix != ""; prefix = nextPrefix(prefix) {
-			if res, ok := m[prefix]; ok {
-				expectedStatus = res
-				break
+			if actualResult.TimedOut {
+				differently = true
 			}
 		}
 
@@ -117,13 +226,23 @@ func (expected *ExpectedResults) Compare(actual map[string]TestResult) (*Compare
 			Status:       actualResult.Status,
 			Output:       o,
 			Measurements: actualResult.Measurements,
+			Attempts:     actualResult.Attempts,
+			TimedOut:     actualResult.TimedOut,
+		}
+
+		if tr.Attempts > 1 && tr.Status == expectedStatus && !differently {
+			res.Flaky[test] = tr
 		}
 
 		switch expectedStatus {
 		case Fail:
 			switch actualResult.Status {
 			case Fail:
-				res.Failed[test] = tr
+				if differently {
+					res.FailedDifferently[test] = tr
+				} else {
+					res.Failed[test] = tr
+				}
 			case Skip:
 				res.XSkipped[test] = tr
 			case Pass:
@@ -163,7 +282,8 @@ func (expected *ExpectedResults) Compare(actual map[string]TestResult) (*Compare
 			}
 
 		case Ignore:
-			continue
+			res.Ignored[test] = tr
+
 		case Unknown:
 			fallthrough
 		default:
@@ -171,6 +291,17 @@ func (expected *ExpectedResults) Compare(actual map[string]TestResult) (*Compare
 		}
 	}
 
+	if expected.Filter == nil {
+		res.Stale = m.stale(tests)
+
+		// bounds of measurements are set for exact test names and checked only for passed tests
+		for test := range expected.Measurements {
+			if _, ok := actual[test]; !ok && res.Stale[test] == "" {
+				res.Stale[test] = Pass
+			}
+		}
+	}
+
 	res.Stats = Stats{
 		Failed:   len(res.Failed),
 		Skipped:  len(res.Skipped),
@@ -178,7 +309,26 @@ func (expected *ExpectedResults) Compare(actual map[string]TestResult) (*Compare
 		XFailed:  len(res.XFailed),
 		XSkipped: len(res.XSkipped),
 		XPassed:  len(res.XPassed),
-		Unknown:  len(res.Unknown),
+
+		FailedDifferently: len(res.FailedDifferently),
+
+		Unknown: len(res.Unknown),
+		Flaky:   len(res.Flaky),
+	}
+
+	for _, tr := range res.Ignored {
+		switch tr.Status {
+		case Fail:
+			res.Stats.IgnoredFailed++
+		case Skip:
+			res.Stats.IgnoredSkipped++
+		case Pass:
+			res.Stats.IgnoredPassed++
+		case Ignore, Unknown:
+			fallthrough
+		default:
+			// not counted
+		}
 	}
 
 	return res, nil
@@ -203,3 +353,35 @@ func nextPrefix(path string) string {
 
 	return path[:i+1]
 }
+
+// errorLinesRE matches output lines that likely describe the failure.
+var errorLinesRE = regexp.MustCompile(`(?i)error|fail|panic|exception`)
+
+// errorLines is the maximum number of relevant output lines of tests that failed differently.
+const errorLines = 5
+
+// failedDifferentlyOutput returns the output of the test that failed differently,
+// prefixed by the expected error and the last relevant output lines
+// (the last lines if none of them look relevant).
+func failedDifferentlyOutput(re *regexp.Regexp, output string) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	var relevant []string
+
+	for _, l := range lines {
+		if errorLinesRE.MatchString(l) {
+			relevant = append(relevant, l)
+		}
+	}
+
+	if len(relevant) == 0 {
+		relevant = lines
+	}
+
+	relevant = relevant[max(len(relevant)-errorLines, 0):]
+
+	return fmt.Sprintf(
+		"Expected error: %s\nActual error:\n%s\n\n%s",
+		re, strings.Join(relevant, "\n"), output,
+	)
+}
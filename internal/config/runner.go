@@ -14,6 +14,8 @@
 
 package config
 
+import "time"
+
 // RunnerType represents the type of test runner used in the project configuration.
 type RunnerType string
 
@@ -45,8 +47,9 @@ type RunnerParamsCommand struct {
 
 // RunnerParamsCommandTest represents a single test in `command` runner parameters.
 type RunnerParamsCommandTest struct {
-	Name string
-	Cmd  string
+	Name    string
+	Cmd     string
+	Timeout time.Duration // if set, bounds that test
 }
 
 // runnerParams implements [RunnerParams] interface.
@@ -56,6 +59,13 @@ func (rp *RunnerParamsCommand) runnerParams() {}
 type RunnerParamsGoTest struct {
 	Dir  string
 	Args []string
+
+	// `go test -run` and `-skip` flags values, if set
+	Run  string
+	Skip string
+
+	// if set, only top-level tests of that shard are run
+	Shard *Shard
 }
 
 // runnerParams implements [RunnerParams] interface.
@@ -0,0 +1,61 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Shard represents a part of tests when they are split between several runs.
+type Shard struct {
+	Index int // from 1 to Total
+	Total int
+}
+
+// ParseShard parses shard in "i/n" form.
+func ParseShard(s string) (*Shard, error) {
+	var res Shard
+
+	if _, err := fmt.Sscanf(s, "%d/%d", &res.Index, &res.Total); err != nil {
+		return nil, fmt.Errorf("invalid shard %q: %w", s, err)
+	}
+
+	if res.Total < 1 || res.Index < 1 || res.Index > res.Total || res.String() != s {
+		return nil, fmt.Errorf("invalid shard %q", s)
+	}
+
+	return &res, nil
+}
+
+// String returns shard in "i/n" form.
+func (s *Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Total)
+}
+
+// Match returns true if the given name belongs to the shard.
+// Nil shard matches all names.
+//
+// Names are distributed by stable hash, so the result does not depend on other names.
+func (s *Shard) Match(name string) bool {
+	if s == nil {
+		return true
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+
+	return int(h.Sum32()%uint32(s.Total)) == s.Index-1
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShard(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"0/2", "3/2", "1/0", "1", "a/b", "1/2/3", "01/2"} {
+		_, err := ParseShard(s)
+		assert.Error(t, err, "shard = %q", s)
+	}
+
+	const total = 3
+
+	shards := make([]*Shard, total)
+
+	for i := range shards {
+		var err error
+		shards[i], err = ParseShard(fmt.Sprintf("%d/%d", i+1, total))
+		require.NoError(t, err)
+	}
+
+	counts := make([]int, total)
+
+	for i := range 300 {
+		name := fmt.Sprintf("TestName%d", i)
+
+		var matched int
+
+		for j, s := range shards {
+			if s.Match(name) {
+				matched++
+				counts[j]++
+			}
+		}
+
+		assert.Equal(t, 1, matched, "name = %s", name)
+	}
+
+	for i, c := range counts {
+		assert.Greater(t, c, 50, "shard = %d", i+1)
+	}
+
+	assert.True(t, (*Shard)(nil).Match("TestName"))
+}
@@ -24,5 +24,14 @@ type Stats struct {
 	XSkipped int
 	XPassed  int
 
+	FailedDifferently int `yaml:"failed_differently"` // unexpected, see [CompareResults]
+
 	Unknown int
+
+	Flaky int `yaml:"-"` // expected results after retries; not a part of expected stats
+
+	// actual results of ignored tests; not a part of expected stats
+	IgnoredFailed  int `yaml:"-"`
+	IgnoredSkipped int `yaml:"-"`
+	IgnoredPassed  int `yaml:"-"`
 }
@@ -0,0 +1,313 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configload
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// Bless rewrites expected results for the given database in the project configuration YAML file.
+//
+// Only fail/skip/pass lists and stats of that database are changed.
+// The rest of the file, including comments of names that are left in lists, is preserved.
+func Bless(file, db string, expected *config.ExpectedResults) error {
+	fi, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read project config file: %w", err)
+	}
+
+	content, err := blessContent(string(b), db, expected)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, []byte(content), fi.Mode().Perm())
+}
+
+// blessContent returns project configuration YAML content with rewritten expected results for the given database.
+func blessContent(content, db string, expected *config.ExpectedResults) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse project config: %w", err)
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("unexpected project config structure")
+	}
+
+	root := doc.Content[0]
+
+	i := mappingIndex(root, "results")
+	if i < 0 || root.Content[i+1].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("no results in project config")
+	}
+
+	results := root.Content[i+1]
+
+	// the block ends where the next key of results or the next top-level key starts
+	var next *yaml.Node
+	if i+2 < len(root.Content) {
+		next = root.Content[i+2]
+	}
+
+	j := mappingIndex(results, db)
+	if j < 0 {
+		return "", fmt.Errorf("no results for %q in project config", db)
+	}
+
+	key, value := results.Content[j], results.Content[j+1]
+
+	if j+2 < len(results.Content) {
+		next = results.Content[j+2]
+	}
+
+	if value.Kind != yaml.MappingNode {
+		if value.Tag != "!!null" {
+			return "", fmt.Errorf("unexpected results structure for %q", db)
+		}
+
+		*value = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	setStats(value, expected.Stats)
+
+	for _, g := range []struct {
+		key   string
+		names []string
+	}{
+		{"fail", expected.Fail},
+		{"skip", expected.Skip},
+		{"pass", expected.Pass},
+	} {
+		setNames(value, g.key, g.names)
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+
+	start := key.Line - 1
+	end := len(lines)
+
+	if next != nil {
+		end = next.Line - 1
+	}
+
+	// leave blank lines and comments of the next key in place
+	for end > start+1 {
+		line := lines[end-1]
+		trimmed := strings.TrimSpace(line)
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if trimmed != "" && !(strings.HasPrefix(trimmed, "#") && indent < key.Column) {
+			break
+		}
+
+		end--
+	}
+
+	// head comment is located above the replaced block
+	k := *key
+	k.HeadComment = ""
+
+	var buf bytes.Buffer
+
+	e := yaml.NewEncoder(&buf)
+	e.SetIndent(2)
+
+	if err := e.Encode(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{&k, value}}); err != nil {
+		return "", err
+	}
+
+	if err := e.Close(); err != nil {
+		return "", err
+	}
+
+	prefix := strings.Repeat(" ", key.Column-1)
+
+	var res strings.Builder
+
+	for _, line := range lines[:start] {
+		res.WriteString(line)
+	}
+
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			res.WriteString(prefix)
+		}
+
+		res.WriteString(line)
+	}
+
+	for _, line := range lines[end:] {
+		res.WriteString(line)
+	}
+
+	return res.String(), nil
+}
+
+// mappingIndex returns the index of the given key in the mapping node content, or -1.
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// setStats updates stats in the mapping node of expected results, keeping the order of existing keys.
+func setStats(node *yaml.Node, stats *config.Stats) {
+	i := mappingIndex(node, "stats")
+	if i < 0 {
+		node.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "stats"},
+			{Kind: yaml.MappingNode, Tag: "!!map"},
+		}, node.Content...)
+		i = 0
+	}
+
+	s := node.Content[i+1]
+	*s = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: s.Content}
+
+	for _, g := range []struct {
+		key   string
+		value int
+	}{
+		{"fail", stats.Failed},
+		{"skip", stats.Skipped},
+		{"pass", stats.Passed},
+	} {
+		j := mappingIndex(s, g.key)
+
+		if g.value == 0 {
+			if j >= 0 {
+				s.Content = slices.Delete(s.Content, j, j+2)
+			}
+
+			continue
+		}
+
+		v := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(g.value)}
+
+		if j < 0 {
+			s.Content = append(s.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: g.key}, v)
+			continue
+		}
+
+		v.LineComment = s.Content[j+1].LineComment
+		s.Content[j+1] = v
+	}
+}
+
+// entryName returns the test name of the list item node that is either a string or a mapping with name.
+func entryName(item *yaml.Node) string {
+	if item.Kind != yaml.MappingNode {
+		return item.Value
+	}
+
+	if i := mappingIndex(item, "name"); i >= 0 {
+		return item.Content[i+1].Value
+	}
+
+	return ""
+}
+
+// setNames updates the list of test names with the given key in the mapping node of expected results.
+//
+// Nodes of names that are left in the list are reused to preserve their comments;
+// head comments of removed names are moved to the next name.
+// New names are inserted before the first greater name to keep sorted lists sorted.
+func setNames(node *yaml.Node, key string, names []string) {
+	i := mappingIndex(node, key)
+
+	if len(names) == 0 {
+		if i >= 0 {
+			node.Content = slices.Delete(node.Content, i, i+2)
+		}
+
+		return
+	}
+
+	if i < 0 {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"},
+		)
+		i = len(node.Content) - 2
+	}
+
+	seq := node.Content[i+1]
+
+	var items []*yaml.Node
+	var headComment string
+
+	for _, item := range seq.Content {
+		if !slices.Contains(names, entryName(item)) {
+			// keep comments that may be related to the following names
+			if item.HeadComment != "" {
+				headComment = strings.TrimSpace(headComment + "\n" + item.HeadComment)
+			}
+
+			continue
+		}
+
+		if headComment != "" {
+			item.HeadComment = strings.TrimSpace(headComment + "\n" + item.HeadComment)
+			headComment = ""
+		}
+
+		items = append(items, item)
+	}
+
+	for _, name := range names {
+		if slices.ContainsFunc(items, func(item *yaml.Node) bool { return entryName(item) == name }) {
+			continue
+		}
+
+		item := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+
+		j := slices.IndexFunc(items, func(item *yaml.Node) bool { return entryName(item) > name })
+		if j < 0 {
+			items = append(items, item)
+			continue
+		}
+
+		// the comment of the first name is usually related to the whole list
+		if j == 0 {
+			item.HeadComment, items[j].HeadComment = items[j].HeadComment, ""
+		}
+
+		items = slices.Insert(items, j, item)
+	}
+
+	seq.Kind = yaml.SequenceNode
+	seq.Tag = "!!seq"
+	seq.Style = 0
+	seq.Content = items
+}
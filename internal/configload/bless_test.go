@@ -0,0 +1,53 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+func TestBless(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile(filepath.Join("testdata", "bless.yml"))
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile(filepath.Join("testdata", "bless_ferretdb2.yml"))
+	require.NoError(t, err)
+
+	res := &config.ExpectedResults{
+		Stats: &config.Stats{
+			Failed:  2,
+			Skipped: 1,
+			Passed:  2,
+		},
+		Fail: []string{"sha1", "strict"},
+		Skip: []string{"plain"},
+	}
+
+	actual, err := blessContent(string(b), "ferretdb2", res)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), actual)
+
+	_, err = blessContent(string(b), "mongodb-secured", res)
+	require.EqualError(t, err, `no results for "mongodb-secured" in project config`)
+}
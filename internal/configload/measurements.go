@@ -0,0 +1,54 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configload
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// bound represents expected bounds of a single measurement in the project configuration YAML file.
+type bound struct {
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+}
+
+// convertMeasurements converts bounds of measurements by test name and measurement name.
+func convertMeasurements(ms map[string]map[string]bound) (map[string]map[string]config.Bound, error) {
+	if len(ms) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[string]map[string]config.Bound, len(ms))
+
+	for test, bounds := range ms {
+		res[test] = make(map[string]config.Bound, len(bounds))
+
+		for name, b := range bounds {
+			if b.Min == nil && b.Max == nil {
+				return nil, fmt.Errorf("measurement %q of test %q has no bounds", name, test)
+			}
+
+			if b.Min != nil && b.Max != nil && *b.Min > *b.Max {
+				return nil, fmt.Errorf("measurement %q of test %q has min greater than max", name, test)
+			}
+
+			res[test][name] = config.Bound{Min: b.Min, Max: b.Max}
+		}
+	}
+
+	return res, nil
+}
@@ -16,8 +16,11 @@ package configload
 
 import (
 	"fmt"
+	"net/url"
 	"slices"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/FerretDB/dance/internal/config"
 )
 
@@ -28,11 +31,60 @@ type expectedResults struct {
 	Default config.Status `yaml:"default"` // defaults to pass
 	Stats   stats         `yaml:"stats"`
 
-	// test names
-	Fail   []string `yaml:"fail"`
-	Skip   []string `yaml:"skip"`
-	Pass   []string `yaml:"pass"`
-	Ignore []string `yaml:"ignore"`
+	// test names, prefixes, and patterns
+	Fail   []entry `yaml:"fail"`
+	Skip   []entry `yaml:"skip"`
+	Pass   []entry `yaml:"pass"`
+	Ignore []entry `yaml:"ignore"`
+
+	// bounds by test name and measurement name
+	Measurements map[string]map[string]bound `yaml:"measurements"`
+}
+
+// entry represents a single entry of expected results lists in the project configuration YAML file.
+//
+// It is either a string or a mapping with name and optional reason, issue URL,
+// and expected error (regular expression that the output of failed tests must match).
+type entry struct {
+	Name   string `yaml:"name"`
+	Reason string `yaml:"reason"`
+	Issue  string `yaml:"issue"`
+	Error  string `yaml:"error"`
+}
+
+// UnmarshalYAML implements [yaml.Unmarshaler] interface.
+func (e *entry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&e.Name)
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: entry should be a string or a mapping", node.Line)
+	}
+
+	// node.Decode does not check unknown fields
+	for i := 0; i < len(node.Content); i += 2 {
+		if k := node.Content[i].Value; !slices.Contains([]string{"name", "reason", "issue", "error"}, k) {
+			return fmt.Errorf("line %d: unknown entry field %q", node.Content[i].Line, k)
+		}
+	}
+
+	type e2 entry // avoid recursion
+	if err := node.Decode((*e2)(e)); err != nil {
+		return err
+	}
+
+	if e.Name == "" {
+		return fmt.Errorf("line %d: entry name is required", node.Line)
+	}
+
+	if e.Issue != "" {
+		if u, err := url.Parse(e.Issue); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("line %d: issue should be a URL, got %q", node.Line, e.Issue)
+		}
+	}
+
+	return nil
 }
 
 // convert converts result to [*config.ExpectedResults].
@@ -55,22 +107,53 @@ func (r *expectedResults) convert() (*config.ExpectedResults, error) {
 		return nil, fmt.Errorf("invalid default status %q", res.Default)
 	}
 
+	var err error
+	if res.Measurements, err = convertMeasurements(r.Measurements); err != nil {
+		return nil, err
+	}
+
 	names := make(map[string]struct{})
 
-	for dst, src := range map[*[]string][]string{
+	for dst, src := range map[*[]string][]entry{
 		&res.Fail:   r.Fail,
 		&res.Skip:   r.Skip,
 		&res.Pass:   r.Pass,
 		&res.Ignore: r.Ignore,
 	} {
-		for _, name := range src {
-			if _, ok := names[name]; ok {
-				return nil, fmt.Errorf("duplicate test name: %q", name)
+		for _, e := range src {
+			if _, ok := names[e.Name]; ok {
+				return nil, fmt.Errorf("duplicate test name: %q", e.Name)
 			}
-			names[name] = struct{}{}
+			names[e.Name] = struct{}{}
+
+			*dst = append(*dst, e.Name)
+
+			if e.Error != "" {
+				if dst != &res.Fail {
+					return nil, fmt.Errorf("expected error is set for %q that is not in the fail list", e.Name)
+				}
+
+				if res.Errors == nil {
+					res.Errors = make(map[string]string)
+				}
+
+				res.Errors[e.Name] = e.Error
+			}
+
+			if e.Reason == "" && e.Issue == "" {
+				continue
+			}
+
+			if res.Info == nil {
+				res.Info = make(map[string]config.EntryInfo)
+			}
+
+			res.Info[e.Name] = config.EntryInfo{Reason: e.Reason, Issue: e.Issue}
 		}
+	}
 
-		*dst = append(*dst, src...)
+	if err := res.Validate(); err != nil {
+		return nil, err
 	}
 
 	return res, nil
@@ -16,6 +16,7 @@ package configload
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/FerretDB/dance/internal/config"
 )
@@ -33,8 +34,9 @@ type runnerParamsCommand struct {
 	Setup    string `yaml:"setup"`
 	Teardown string `yaml:"teardown"`
 	Tests    []struct {
-		Name string `yaml:"name"`
-		Cmd  string `yaml:"cmd"`
+		Name    string        `yaml:"name"`
+		Cmd     string        `yaml:"cmd"`
+		Timeout time.Duration `yaml:"timeout"`
 	} `yaml:"tests"`
 }
 
@@ -51,9 +53,14 @@ func (rp *runnerParamsCommand) convert() (config.RunnerParams, error) {
 	}
 
 	for _, t := range rp.Tests {
+		if t.Timeout < 0 {
+			return nil, fmt.Errorf("timeout of test %q must not be negative", t.Name)
+		}
+
 		res.Tests = append(res.Tests, config.RunnerParamsCommandTest{
-			Name: t.Name,
-			Cmd:  t.Cmd,
+			Name:    t.Name,
+			Cmd:     t.Cmd,
+			Timeout: t.Timeout,
 		})
 	}
 
@@ -68,6 +75,10 @@ type runnerParamsGoTest struct {
 
 // convert implements [runnerParams] interface.
 func (rp *runnerParamsGoTest) convert() (config.RunnerParams, error) {
+	if rp.Dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+
 	return &config.RunnerParamsGoTest{
 		Dir:  rp.Dir,
 		Args: rp.Args,
@@ -82,6 +93,10 @@ type runnerParamsYCSB struct {
 
 // convert implements [runnerParams] interface.
 func (rp *runnerParamsYCSB) convert() (config.RunnerParams, error) {
+	if rp.Dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+
 	return &config.RunnerParamsYCSB{
 		Dir:  rp.Dir,
 		Args: rp.Args,
@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configload
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// Validate loads the project configuration YAML file for all known databases and returns all found problems.
+//
+// If run is not nil, results for databases that are not in that list are reported too.
+func Validate(file string, run []string) []error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return []error{fmt.Errorf("failed to read project config file: %w", err)}
+	}
+
+	return validateContent(string(b), run)
+}
+
+// validateContent validates project configuration YAML content for all known databases.
+func validateContent(content string, run []string) []error {
+	// the same problem is usually reported for many databases
+	var msgs []string
+	dbs := make(map[string][]string)
+
+	report := func(db, msg string) {
+		if _, ok := dbs[msg]; !ok {
+			msgs = append(msgs, msg)
+		}
+
+		dbs[msg] = append(dbs[msg], db)
+	}
+
+	for _, db := range slices.Sorted(maps.Keys(DBs)) {
+		c, err := loadContent(content, db)
+		if err != nil {
+			report(db, err.Error())
+			continue
+		}
+
+		if c == nil {
+			continue
+		}
+
+		for _, msg := range validateStats(c.Results) {
+			report(db, msg)
+		}
+	}
+
+	var res []error
+
+	for _, msg := range msgs {
+		if len(dbs[msg]) == len(DBs) {
+			res = append(res, fmt.Errorf("%s", msg))
+			continue
+		}
+
+		res = append(res, fmt.Errorf("%s: %s", strings.Join(dbs[msg], ", "), msg))
+	}
+
+	if run == nil {
+		return res
+	}
+
+	// only keys are needed, other problems are reported above
+	var pc struct {
+		Results map[string]yaml.Node `yaml:"results"`
+	}
+
+	if err := yaml.Unmarshal([]byte(content), &pc); err != nil {
+		return res
+	}
+
+	for _, db := range slices.Sorted(maps.Keys(pc.Results)) {
+		if !slices.Contains(run, db) {
+			res = append(res, fmt.Errorf("%s: results for the database that is not run", db))
+		}
+	}
+
+	return res
+}
+
+// validateStats returns problems with expected stats that can't be consistent with lists of test names.
+//
+// Each name is assumed to match at least one test.
+func validateStats(res *config.ExpectedResults) []string {
+	var msgs []string
+
+	for _, g := range []struct {
+		status config.Status
+		names  []string
+		stat   int
+	}{
+		{config.Fail, res.Fail, res.Stats.Failed},
+		{config.Skip, res.Skip, res.Stats.Skipped},
+		{config.Pass, res.Pass, res.Stats.Passed},
+	} {
+		if res.Default == g.status {
+			continue
+		}
+
+		if len(g.names) > g.stat {
+			msgs = append(msgs, fmt.Sprintf(
+				"%d test names in %s list, but only %d in stats.%s", len(g.names), g.status, g.stat, g.status,
+			))
+		}
+	}
+
+	return msgs
+}
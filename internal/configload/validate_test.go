@@ -0,0 +1,65 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configload
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Projects", func(t *testing.T) {
+		t.Parallel()
+
+		files, err := filepath.Glob(filepath.Join("..", "..", "projects", "*.yml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+
+		for _, file := range files {
+			assert.Empty(t, Validate(file, nil), "file = %s", file)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		errs := Validate(filepath.Join("testdata", "invalid_stats.yml"), []string{"mongodb", "ferretdb-postgresql"})
+
+		var actual []string
+		for _, err := range errs {
+			actual = append(actual, err.Error())
+		}
+
+		expected := []string{
+			"ferretdb-postgresql, mongodb: 2 test names in fail list, but only 1 in stats.fail",
+			"ferretdb2: results for the database that is not run",
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("NoDir", func(t *testing.T) {
+		t.Parallel()
+
+		errs := Validate(filepath.Join("testdata", "command_nodir.yml"), nil)
+
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "failed to convert runner parameters: dir is required")
+	})
+}
@@ -126,14 +126,22 @@ func (c *Client) ping(ctx context.Context) {
 }
 
 // Push pushes test results to MongoDB-compatible database.
-func (c *Client) Push(ctx context.Context, config, database string, res map[string]config.TestResult) error {
+func (c *Client) Push(ctx context.Context, config, database string, res, ignored map[string]config.TestResult) error {
 	var passed bson.D
 
 	for t, tr := range res {
-		t = strings.ReplaceAll(t, ".", "_") // to make it compatible with FerretDB v1
+		t = TestKey(t)
 		passed = append(passed, bson.E{t, bson.D{{"m", tr.Measurements}}})
 	}
 
+	// actual statuses of ignored tests
+	ignoredDoc := bson.D{}
+
+	for t, tr := range ignored {
+		t = TestKey(t)
+		ignoredDoc = append(ignoredDoc, bson.E{t, bson.D{{"s", string(tr.Status)}}})
+	}
+
 	doc := bson.D{
 		{"config", config},
 		{"database", database},
@@ -144,6 +152,7 @@ func (c *Client) Push(ctx context.Context, config, database string, res map[stri
 			{"repository", c.repository},
 		}},
 		{"passed", passed},
+		{"ignored", ignoredDoc},
 	}
 
 	c.l.InfoContext(ctx, "Pushing results to MongoDB URI...", slog.Any("doc", doc))
@@ -155,6 +164,63 @@ func (c *Client) Push(ctx context.Context, config, database string, res map[stri
 	return err
 }
 
+// TestKey returns the key of the given test name in pushed documents.
+func TestKey(test string) string {
+	return strings.ReplaceAll(test, ".", "_") // to make it compatible with FerretDB v1
+}
+
+// Fetch returns measurements of passed tests from up to n latest pushed results
+// of the given project configuration and database, latest first.
+//
+// Only results pushed from the same runner machine (see RUNNER_NAME) are returned,
+// so measurements of different hardware are not compared.
+// Test names are returned as keys (see [TestKey]).
+func (c *Client) Fetch(ctx context.Context, config, database string, n int) ([]map[string]map[string]float64, error) {
+	filter := bson.D{
+		{"config", config},
+		{"database", database},
+		{"env.runner", c.runner},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{"time", -1}}).
+		SetLimit(int64(n)).
+		SetProjection(bson.D{{"passed", 1}})
+
+	c.l.InfoContext(ctx, "Fetching results from MongoDB URI...", slog.Any("filter", filter), slog.Int("limit", n))
+
+	c.ping(ctx)
+
+	cur, err := c.c.Database(c.database).Collection("incoming").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		Passed map[string]struct {
+			M map[string]float64 `bson:"m"`
+		} `bson:"passed"`
+	}
+
+	if err = cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	res := make([]map[string]map[string]float64, len(docs))
+
+	for i, doc := range docs {
+		res[i] = make(map[string]map[string]float64, len(doc.Passed))
+
+		for t, p := range doc.Passed {
+			if len(p.M) > 0 {
+				res[i][t] = p.M
+			}
+		}
+	}
+
+	return res, nil
+}
+
 // Close closes all connections.
 //
 // TODO https://github.com/FerretDB/dance/issues/1122
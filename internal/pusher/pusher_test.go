@@ -17,9 +17,11 @@ package pusher
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/FerretDB/dance/internal/config"
 )
@@ -35,7 +37,48 @@ func TestPusher(t *testing.T) {
 
 	res := map[string]config.TestResult{
 		"github.com/FerretDB/dance/projects/mongo-tools/TestExportImport": {},
+		"read": {Status: config.Pass, Measurements: map[string]float64{"ops": 1234.5}},
 	}
-	err = c.Push(context.Background(), "mongo-tools.yml", "ferretdb-postgresql", res)
+	ignored := map[string]config.TestResult{
+		"github.com/FerretDB/dance/projects/mongo-tools/TestFlaky": {Status: config.Fail},
+	}
+	err = c.Push(context.Background(), "mongo-tools.yml", "ferretdb-postgresql", res, ignored)
+	require.NoError(t, err)
+
+	fetched, err := c.Fetch(context.Background(), "mongo-tools.yml", "ferretdb-postgresql", 1)
+	require.NoError(t, err)
+	require.Len(t, fetched, 1)
+	assert.Equal(t, map[string]float64{"ops": 1234.5}, fetched[0]["read"])
+}
+
+func TestFetchOtherRunner(t *testing.T) {
+	t.Parallel()
+
+	c, err := New("mongodb://localhost:27001/dance", Logger(t))
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	ctx := context.Background()
+
+	res := map[string]config.TestResult{
+		"update": {Status: config.Pass, Measurements: map[string]float64{"ops": 1234.5}},
+	}
+	err = c.Push(ctx, "ycsb-workloada.yml", "ferretdb-postgresql-runners", res, nil)
+	require.NoError(t, err)
+
+	// later results pushed from another runner machine
+	doc := bson.D{
+		{"config", "ycsb-workloada.yml"},
+		{"database", "ferretdb-postgresql-runners"},
+		{"time", time.Now().Add(time.Minute)},
+		{"env", bson.D{{"runner", c.runner + "-other"}}},
+		{"passed", bson.D{{"update", bson.D{{"m", bson.D{{"ops", 4321.5}}}}}}},
+	}
+	_, err = c.c.Database(c.database).Collection("incoming").InsertOne(ctx, doc)
+	require.NoError(t, err)
+
+	fetched, err := c.Fetch(ctx, "ycsb-workloada.yml", "ferretdb-postgresql-runners", 1)
 	require.NoError(t, err)
+	require.Len(t, fetched, 1)
+	assert.Equal(t, map[string]float64{"ops": 1234.5}, fetched[0]["update"])
 }
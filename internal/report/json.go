@@ -0,0 +1,157 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// jsonResult represents a single JSON document of the report.
+type jsonResult struct {
+	Config          string     `json:"config"`
+	Database        string     `json:"database"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	ExpectedStats   *jsonStats `json:"expected_stats"`
+	ActualStats     *jsonStats `json:"actual_stats"`
+
+	Failed  map[string]jsonTest `json:"failed"`
+	Skipped map[string]jsonTest `json:"skipped"`
+	Passed  map[string]jsonTest `json:"passed"`
+
+	XFailed  map[string]jsonTest `json:"xfailed"`
+	XSkipped map[string]jsonTest `json:"xskipped"`
+	XPassed  map[string]jsonTest `json:"xpassed"`
+
+	FailedDifferently map[string]jsonTest `json:"failed_differently"`
+
+	Unknown map[string]jsonTest `json:"unknown"`
+
+	Flaky map[string]jsonTest `json:"flaky"`
+
+	Ignored map[string]jsonTest `json:"ignored"`
+
+	Stale map[string]config.Status `json:"stale,omitempty"`
+}
+
+// jsonStats represents [config.Stats] in the report.
+type jsonStats struct {
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+	Passed  int `json:"passed"`
+
+	XFailed  int `json:"xfailed"`
+	XSkipped int `json:"xskipped"`
+	XPassed  int `json:"xpassed"`
+
+	FailedDifferently int `json:"failed_differently"`
+
+	Unknown int `json:"unknown"`
+
+	Flaky int `json:"flaky"`
+
+	IgnoredFailed  int `json:"ignored_failed"`
+	IgnoredSkipped int `json:"ignored_skipped"`
+	IgnoredPassed  int `json:"ignored_passed"`
+}
+
+// jsonTest represents [config.TestResult] in the report.
+type jsonTest struct {
+	Status       config.Status      `json:"status"`
+	Output       string             `json:"output,omitempty"`
+	Measurements map[string]float64 `json:"measurements,omitempty"`
+	Attempts     int                `json:"attempts,omitempty"`
+	TimedOut     bool               `json:"timed_out,omitempty"`
+	Reason       string             `json:"reason,omitempty"`
+	Issue        string             `json:"issue,omitempty"`
+}
+
+// newJSONStats converts [*config.Stats] to [*jsonStats].
+func newJSONStats(s *config.Stats) *jsonStats {
+	if s == nil {
+		return nil
+	}
+
+	return &jsonStats{
+		Failed:   s.Failed,
+		Skipped:  s.Skipped,
+		Passed:   s.Passed,
+		XFailed:  s.XFailed,
+		XSkipped: s.XSkipped,
+		XPassed:  s.XPassed,
+
+		FailedDifferently: s.FailedDifferently,
+
+		Unknown: s.Unknown,
+		Flaky:   s.Flaky,
+
+		IgnoredFailed:  s.IgnoredFailed,
+		IgnoredSkipped: s.IgnoredSkipped,
+		IgnoredPassed:  s.IgnoredPassed,
+	}
+}
+
+// newJSONTests converts test results to JSON representation.
+func newJSONTests(res map[string]config.TestResult, info map[string]config.EntryInfo) map[string]jsonTest {
+	tests := make(map[string]jsonTest, len(res))
+
+	for t, tr := range res {
+		tests[t] = jsonTest{
+			Status:       tr.Status,
+			Output:       RawOutput(tr),
+			Measurements: tr.Measurements,
+			Attempts:     tr.Attempts,
+			TimedOut:     tr.TimedOut,
+			Reason:       info[t].Reason,
+			Issue:        info[t].Issue,
+		}
+	}
+
+	return tests
+}
+
+// WriteJSON writes results as a stream of JSON documents, one per line for each pair.
+func WriteJSON(w io.Writer, results []*Result) error {
+	e := json.NewEncoder(w)
+
+	for _, r := range results {
+		doc := jsonResult{
+			Config:            r.Config,
+			Database:          r.Database,
+			DurationSeconds:   r.Duration.Seconds(),
+			ExpectedStats:     newJSONStats(r.Expected),
+			ActualStats:       newJSONStats(&r.Compare.Stats),
+			Failed:            newJSONTests(r.Compare.Failed, r.Compare.Info),
+			Skipped:           newJSONTests(r.Compare.Skipped, r.Compare.Info),
+			Passed:            newJSONTests(r.Compare.Passed, r.Compare.Info),
+			XFailed:           newJSONTests(r.Compare.XFailed, r.Compare.Info),
+			XSkipped:          newJSONTests(r.Compare.XSkipped, r.Compare.Info),
+			XPassed:           newJSONTests(r.Compare.XPassed, r.Compare.Info),
+			FailedDifferently: newJSONTests(r.Compare.FailedDifferently, r.Compare.Info),
+			Unknown:           newJSONTests(r.Compare.Unknown, r.Compare.Info),
+			Flaky:             newJSONTests(r.Compare.Flaky, r.Compare.Info),
+			Ignored:           newJSONTests(r.Compare.Ignored, r.Compare.Info),
+			Stale:             r.Compare.Stale,
+		}
+
+		if err := e.Encode(doc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	results := testResults(t)
+	results = append(results, results[0])
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, results))
+
+	d := json.NewDecoder(&buf)
+
+	for range results {
+		var actual map[string]any
+		require.NoError(t, d.Decode(&actual))
+
+		assert.Equal(t, "python-example.yml", actual["config"])
+		assert.Equal(t, "mongodb", actual["database"])
+		assert.Equal(t, 1.5, actual["duration_seconds"])
+
+		expectedStats := map[string]any{
+			"failed": 1.0, "skipped": 0.0, "passed": 2.0,
+			"xfailed": 0.0, "xskipped": 0.0, "xpassed": 0.0,
+			"failed_differently": 0.0, "unknown": 0.0, "flaky": 0.0,
+			"ignored_failed": 0.0, "ignored_skipped": 0.0, "ignored_passed": 0.0,
+		}
+		assert.Equal(t, expectedStats, actual["expected_stats"])
+
+		actualStats := map[string]any{
+			"failed": 1.0, "skipped": 1.0, "passed": 1.0,
+			"xfailed": 1.0, "xskipped": 0.0, "xpassed": 1.0,
+			"failed_differently": 1.0, "unknown": 0.0, "flaky": 0.0,
+			"ignored_failed": 1.0, "ignored_skipped": 0.0, "ignored_passed": 0.0,
+		}
+		assert.Equal(t, actualStats, actual["actual_stats"])
+
+		xfailed := map[string]any{
+			"noauth": map[string]any{
+				"status": "fail",
+				"output": "Authentication failed\nexit status 1",
+			},
+		}
+		assert.Equal(t, xfailed, actual["xfailed"])
+
+		failed := map[string]any{
+			"plain": map[string]any{
+				"status": "fail",
+				"output": "exit status 1",
+				"reason": "PLAIN is not supported",
+				"issue":  "https://github.com/FerretDB/FerretDB/issues/1",
+			},
+		}
+		assert.Equal(t, failed, actual["failed"])
+		assert.Equal(t, map[string]any{"removed": "fail"}, actual["stale"])
+
+		ignored := map[string]any{
+			"flaky": map[string]any{
+				"status": "fail",
+				"output": "timeout",
+			},
+		}
+		assert.Equal(t, ignored, actual["ignored"])
+		assert.Equal(t, map[string]any{}, actual["unknown"])
+	}
+
+	assert.False(t, d.More())
+}
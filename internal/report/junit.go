@@ -0,0 +1,163 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// junitTestSuites represents the root element of JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite represents a single project configuration and database pair.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     float64         `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase represents a single test.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage represents failure, error, or skipped element.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// junitClassName returns the class name of test cases of the given pair.
+//
+// JUnit viewers split class names on dots into packages,
+// so the configuration file extension is removed and other dots are replaced.
+func junitClassName(r *Result) string {
+	name := strings.TrimSuffix(r.Config, filepath.Ext(r.Config)) + "/" + r.Database
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+// WriteJUnit writes results as JUnit XML report.
+//
+// Each pair is a test suite, and each test is a test case.
+// Unexpectedly failed, skipped, or passed tests are failures, tests with unknown results are errors,
+// and expectedly failed or skipped tests are skipped.
+func WriteJUnit(w io.Writer, results []*Result) error {
+	var root junitTestSuites
+
+	for _, r := range results {
+		suite := junitTestSuite{
+			Name: r.name(),
+			Time: r.Duration.Seconds(),
+		}
+
+		for _, g := range []struct { //nolint:vet // for readability
+			res     map[string]config.TestResult
+			failure string
+			err     string
+			skipped string
+		}{
+			{res: r.Compare.XFailed, failure: "unexpectedly failed"},
+			{res: r.Compare.XSkipped, failure: "unexpectedly skipped"},
+			{res: r.Compare.XPassed, failure: "unexpectedly passed"},
+			{res: r.Compare.FailedDifferently, failure: "failed differently"},
+			{res: r.Compare.Unknown, err: "unknown result"},
+			{res: r.Compare.Failed, skipped: "expectedly failed"},
+			{res: r.Compare.Skipped, skipped: "expectedly skipped"},
+			{res: r.Compare.Passed},
+		} {
+			for t, tr := range g.res {
+				tc := junitTestCase{
+					Name:      t,
+					ClassName: junitClassName(r),
+					SystemOut: RawOutput(tr),
+				}
+
+				// information of expected results entries explains the expected status
+				var info string
+				if i, ok := r.Compare.Info[t]; ok {
+					info = ": " + i.String()
+				}
+
+				switch {
+				case g.failure != "" && tr.TimedOut:
+					tc.Failure = &junitMessage{Message: g.failure + " (timed out)" + info}
+					suite.Failures++
+				case g.failure != "":
+					tc.Failure = &junitMessage{Message: g.failure + info}
+					suite.Failures++
+				case g.err != "":
+					tc.Error = &junitMessage{Message: g.err + info}
+					suite.Errors++
+				case g.skipped != "":
+					tc.Skipped = &junitMessage{Message: g.skipped + info}
+					suite.Skipped++
+				}
+
+				suite.Cases = append(suite.Cases, tc)
+			}
+		}
+
+		slices.SortFunc(suite.Cases, func(a, b junitTestCase) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		suite.Tests = len(suite.Cases)
+
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Errors += suite.Errors
+		root.Skipped += suite.Skipped
+		root.Time += suite.Time
+
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+
+	if err := e.Encode(root); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
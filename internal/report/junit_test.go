@@ -0,0 +1,123 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// testResults returns results used by tests.
+func testResults(t testing.TB) []*Result {
+	t.Helper()
+
+	expected := &config.ExpectedResults{
+		Default: config.Pass,
+		Stats: &config.Stats{
+			Failed: 1,
+			Passed: 2,
+		},
+		Fail:   []string{"sha1", "plain", "removed", "scram"},
+		Skip:   []string{"strict"},
+		Ignore: []string{"flaky"},
+		Errors: map[string]string{"scram": "Authentication failed"},
+		Info: map[string]config.EntryInfo{
+			"plain": {Reason: "PLAIN is not supported", Issue: "https://github.com/FerretDB/FerretDB/issues/1"},
+		},
+	}
+
+	cmp, err := expected.Compare(map[string]config.TestResult{
+		"normal": {Status: config.Pass},
+		"noauth": {Status: config.Fail, Output: "Authentication failed\nexit status 1"},
+		"plain":  {Status: config.Fail, Output: "exit status 1"},
+		"sha1":   {Status: config.Pass},
+		"strict": {Status: config.Skip},
+		"flaky":  {Status: config.Fail, Output: "timeout"},
+		"scram":  {Status: config.Fail, Output: "refused"},
+	})
+	require.NoError(t, err)
+
+	return []*Result{{
+		Config:   "python-example.yml",
+		Database: "mongodb",
+		Expected: expected.Stats,
+		Compare:  cmp,
+		Duration: 1500 * time.Millisecond,
+	}}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnit(&buf, testResults(t)))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="6" failures="3" errors="0" skipped="2" time="1.5">
+  <testsuite name="python-example.yml/mongodb" tests="6" failures="3" errors="0" skipped="2" time="1.5">
+    <testcase name="noauth" classname="python-example/mongodb">
+      <failure message="unexpectedly failed"></failure>
+      <system-out>Authentication failed&#xA;exit status 1</system-out>
+    </testcase>
+    <testcase name="normal" classname="python-example/mongodb"></testcase>
+    <testcase name="plain" classname="python-example/mongodb">
+      <skipped message="expectedly failed: PLAIN is not supported (https://github.com/FerretDB/FerretDB/issues/1)"></skipped>
+      <system-out>exit status 1</system-out>
+    </testcase>
+    <testcase name="scram" classname="python-example/mongodb">
+      <failure message="failed differently"></failure>
+      <system-out>Expected error: Authentication failed&#xA;Actual error:&#xA;refused&#xA;&#xA;refused</system-out>
+    </testcase>
+    <testcase name="sha1" classname="python-example/mongodb">
+      <failure message="unexpectedly passed"></failure>
+    </testcase>
+    <testcase name="strict" classname="python-example/mongodb">
+      <skipped message="expectedly skipped"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestJUnitClassName(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		r        *Result
+		expected string
+	}{
+		"Simple": {
+			r:        &Result{Config: "python-example.yml", Database: "mongodb"},
+			expected: "python-example/mongodb",
+		},
+		"Dots": {
+			r:        &Result{Config: "mongo-tools.v2.yml", Database: "ferretdb-2.1"},
+			expected: "mongo-tools_v2/ferretdb-2_1",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, junitClassName(tc.r))
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// markdownOutputLines is the maximum number of the last output lines included for each test.
+const markdownOutputLines = 30
+
+// TruncateOutput returns the last lines of the test output, up to the given number.
+func TruncateOutput(output string, lines int) string {
+	ls := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(ls) <= lines {
+		return strings.Join(ls, "\n")
+	}
+
+	return fmt.Sprintf("[%d lines skipped]\n", len(ls)-lines) + strings.Join(ls[len(ls)-lines:], "\n")
+}
+
+// markdownStat returns a formatted stat value, or "-" if stats are not known.
+func markdownStat(s *config.Stats, f func(*config.Stats) int) string {
+	if s == nil {
+		return "-"
+	}
+
+	return strconv.Itoa(f(s))
+}
+
+// markdownCode returns the given text as Markdown inline code, even if it contains backticks.
+func markdownCode(s string) string {
+	// delimiters should be longer than any run of backticks inside
+	var longest, run int
+
+	for _, c := range s {
+		if c != '`' {
+			run = 0
+			continue
+		}
+
+		run++
+		longest = max(longest, run)
+	}
+
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+
+	d := strings.Repeat("`", longest+1)
+
+	return d + s + d
+}
+
+// markdownCell returns the given text escaped for a Markdown table cell, including inline code.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// writeMarkdownTests writes a collapsible list of tests with truncated outputs.
+func writeMarkdownTests(w io.Writer, label string, res map[string]config.TestResult, info map[string]config.EntryInfo) error {
+	if len(res) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "<details>\n<summary>%s (%d)</summary>\n\n", label, len(res)); err != nil {
+		return err
+	}
+
+	for _, t := range slices.Sorted(maps.Keys(res)) {
+		var timedOut string
+		if res[t].TimedOut {
+			timedOut = " (timed out)"
+		}
+
+		var expected string
+		if i, ok := info[t]; ok {
+			expected = " — " + i.String()
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s%s\n\n", markdownCode(t), timedOut, expected); err != nil {
+			return err
+		}
+
+		if o := RawOutput(res[t]); o != "" {
+			if _, err := fmt.Fprintf(w, "````\n%s\n````\n\n", TruncateOutput(o, markdownOutputLines)); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, "</details>\n\n")
+
+	return err
+}
+
+// writeMarkdownStale writes a collapsible list of stale entries of expected results, if any.
+func writeMarkdownStale(w io.Writer, stale map[string]config.Status) error {
+	if len(stale) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "<details>\n<summary>Stale expectations (%d)</summary>\n\n", len(stale))
+
+	for _, e := range slices.Sorted(maps.Keys(stale)) {
+		fmt.Fprintf(&sb, "* %s: %s\n", markdownCode(e), stale[e])
+	}
+
+	sb.WriteString("\n</details>\n\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// writeMarkdownMeasurements writes a table of measurements, if any,
+// including those of tests failed because of measurements out of bounds.
+func writeMarkdownMeasurements(w io.Writer, cmp *config.CompareResults) error {
+	tests := make(map[string]map[string]float64)
+	names := make(map[string]struct{})
+
+	for _, res := range []map[string]config.TestResult{
+		cmp.Failed, cmp.Passed, cmp.XFailed, cmp.XPassed, cmp.FailedDifferently,
+	} {
+		for t, tr := range res {
+			if len(tr.Measurements) == 0 {
+				continue
+			}
+
+			tests[t] = tr.Measurements
+
+			for n := range tr.Measurements {
+				names[n] = struct{}{}
+			}
+		}
+	}
+
+	if len(tests) == 0 {
+		return nil
+	}
+
+	cols := slices.Sorted(maps.Keys(names))
+
+	var sb strings.Builder
+
+	sb.WriteString("| Test")
+
+	for _, c := range cols {
+		sb.WriteString(" | " + markdownCell(c))
+	}
+
+	sb.WriteString(" |\n")
+	sb.WriteString("|---" + strings.Repeat("|---:", len(cols)) + "|\n")
+
+	for _, t := range slices.Sorted(maps.Keys(tests)) {
+		sb.WriteString("| " + markdownCell(markdownCode(t)))
+
+		for _, c := range cols {
+			v, ok := tests[t][c]
+			if !ok {
+				sb.WriteString(" | -")
+				continue
+			}
+
+			sb.WriteString(" | " + strconv.FormatFloat(v, 'g', 6, 64))
+		}
+
+		sb.WriteString(" |\n")
+	}
+
+	sb.WriteString("\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// WriteMarkdown writes results as Markdown report suitable for GitHub Actions job summary.
+//
+// Each pair has a stats table, collapsible lists of tests with unexpected or unknown results
+// and of stale expectations, and a measurements table.
+func WriteMarkdown(w io.Writer, results []*Result) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w, "### %s\n\n", r.name()); err != nil {
+			return err
+		}
+
+		var sb strings.Builder
+
+		sb.WriteString("| | Expected | Actual |\n")
+		sb.WriteString("|---|---:|---:|\n")
+
+		for _, row := range []struct {
+			label string
+			f     func(*config.Stats) int
+		}{
+			{"Failed", func(s *config.Stats) int { return s.Failed }},
+			{"Skipped", func(s *config.Stats) int { return s.Skipped }},
+			{"Passed", func(s *config.Stats) int { return s.Passed }},
+			{"Unexpectedly failed", func(s *config.Stats) int { return s.XFailed }},
+			{"Unexpectedly skipped", func(s *config.Stats) int { return s.XSkipped }},
+			{"Unexpectedly passed", func(s *config.Stats) int { return s.XPassed }},
+			{"Failed differently", func(s *config.Stats) int { return s.FailedDifferently }},
+			{"Unknown", func(s *config.Stats) int { return s.Unknown }},
+		} {
+			fmt.Fprintf(
+				&sb, "| %s | %s | %s |\n",
+				row.label, markdownStat(r.Expected, row.f), markdownStat(&r.Compare.Stats, row.f),
+			)
+		}
+
+		sb.WriteString("\n")
+
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+
+		for _, g := range []struct {
+			label string
+			res   map[string]config.TestResult
+		}{
+			{"Unexpectedly failed", r.Compare.XFailed},
+			{"Unexpectedly passed", r.Compare.XPassed},
+			{"Unexpectedly skipped", r.Compare.XSkipped},
+			{"Failed differently", r.Compare.FailedDifferently},
+			{"Unknown", r.Compare.Unknown},
+			{"Flaky", r.Compare.Flaky},
+		} {
+			if err := writeMarkdownTests(w, g.label, g.res, r.Compare.Info); err != nil {
+				return err
+			}
+		}
+
+		if err := writeMarkdownStale(w, r.Compare.Stale); err != nil {
+			return err
+		}
+
+		if err := writeMarkdownMeasurements(w, r.Compare); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	t.Parallel()
+
+	results := testResults(t)
+
+	minOPS := 100.0
+
+	expected := &config.ExpectedResults{
+		Default: config.Pass,
+		Measurements: map[string]map[string]config.Bound{
+			"update": {"ops": {Min: &minOPS}},
+		},
+	}
+
+	cmp, err := expected.Compare(map[string]config.TestResult{
+		"read":   {Status: config.Pass, Measurements: map[string]float64{"ops": 1234.5, "avg": 0.000123}},
+		"update": {Status: config.Pass, Measurements: map[string]float64{"ops": 42}},
+		"re:a|b": {Status: config.Pass, Measurements: map[string]float64{"ops|max": 7}},
+	})
+	require.NoError(t, err)
+
+	results = append(results, &Result{
+		Config:   "ycsb-workloada.yml",
+		Database: "ferretdb2",
+		Compare:  cmp,
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMarkdown(&buf, results))
+
+	expectedMarkdown := "### python-example.yml/mongodb\n\n" +
+		"| | Expected | Actual |\n" +
+		"|---|---:|---:|\n" +
+		"| Failed | 1 | 1 |\n" +
+		"| Skipped | 0 | 1 |\n" +
+		"| Passed | 2 | 1 |\n" +
+		"| Unexpectedly failed | 0 | 1 |\n" +
+		"| Unexpectedly skipped | 0 | 0 |\n" +
+		"| Unexpectedly passed | 0 | 1 |\n" +
+		"| Failed differently | 0 | 1 |\n" +
+		"| Unknown | 0 | 0 |\n\n" +
+		"<details>\n<summary>Unexpectedly failed (1)</summary>\n\n" +
+		"`noauth`\n\n" +
+		"````\nAuthentication failed\nexit status 1\n````\n\n" +
+		"</details>\n\n" +
+		"<details>\n<summary>Unexpectedly passed (1)</summary>\n\n" +
+		"`sha1`\n\n" +
+		"</details>\n\n" +
+		"<details>\n<summary>Failed differently (1)</summary>\n\n" +
+		"`scram`\n\n" +
+		"````\nExpected error: Authentication failed\nActual error:\nrefused\n\nrefused\n````\n\n" +
+		"</details>\n\n" +
+		"<details>\n<summary>Stale expectations (1)</summary>\n\n" +
+		"* `removed`: fail\n\n" +
+		"</details>\n\n" +
+		"### ycsb-workloada.yml/ferretdb2\n\n" +
+		"| | Expected | Actual |\n" +
+		"|---|---:|---:|\n" +
+		"| Failed | - | 0 |\n" +
+		"| Skipped | - | 0 |\n" +
+		"| Passed | - | 2 |\n" +
+		"| Unexpectedly failed | - | 1 |\n" +
+		"| Unexpectedly skipped | - | 0 |\n" +
+		"| Unexpectedly passed | - | 0 |\n" +
+		"| Failed differently | - | 0 |\n" +
+		"| Unknown | - | 0 |\n\n" +
+		"<details>\n<summary>Unexpectedly failed (1)</summary>\n\n" +
+		"`update`\n\n" +
+		"````\nMeasurements out of bounds:\nops = 42 is less than 100\n````\n\n" +
+		"</details>\n\n" +
+		"| Test | avg | ops | ops\\|max |\n" +
+		"|---|---:|---:|---:|\n" +
+		"| `re:a\\|b` | - | - | 7 |\n" +
+		"| `read` | 0.000123 | 1234.5 | - |\n" +
+		"| `update` | - | 42 | - |\n\n"
+	assert.Equal(t, expectedMarkdown, buf.String())
+}
+
+func TestMarkdownCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "`a|b`", markdownCode("a|b"))
+	assert.Equal(t, "``a`b``", markdownCode("a`b"))
+	assert.Equal(t, "``` ``a` ```", markdownCode("``a`"))
+	assert.Equal(t, "``a`b\\|c``", markdownCell(markdownCode("a`b|c")))
+}
+
+func TestTruncateOutput(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a\nb", TruncateOutput("a\nb\n", 2))
+	assert.Equal(t, "[2 lines skipped]\nc\nd", TruncateOutput("a\nb\nc\nd", 2))
+}
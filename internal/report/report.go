@@ -0,0 +1,44 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report provides reports of compared test results in formats readable by other tools.
+package report
+
+import (
+	"strings"
+	"time"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// Result represents compared results of a single project configuration and database pair.
+//
+//nolint:vet // for readability
+type Result struct {
+	Config   string
+	Database string
+	Expected *config.Stats // nil for partial runs
+	Compare  *config.CompareResults
+	Duration time.Duration
+}
+
+// name returns the name of the pair.
+func (r *Result) name() string {
+	return r.Config + "/" + r.Database
+}
+
+// RawOutput returns the test output without indentation added by [config.TestResult.IndentedOutput].
+func RawOutput(tr config.TestResult) string {
+	return strings.ReplaceAll(tr.Output, "\n\t", "\n")
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// History returns up to n latest runs of the given project configuration file and database on the given host
+// that started before the given time, found in the given directory and its subdirectories.
+//
+// Other JSON files, like measurements written by --artifacts flag, and runs without results
+// (like those of shards without tests) are skipped.
+func History(dir, configFile, db, hostname string, before time.Time, n int) ([]*Run, error) {
+	var res []*Run
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		run, err := Load(path)
+		if err != nil {
+			return nil
+		}
+
+		if run.Config != filepath.Base(configFile) || run.Database != db || run.Hostname != hostname {
+			return nil
+		}
+
+		if len(run.Results) == 0 {
+			return nil
+		}
+
+		if !run.Start.Before(before) {
+			return nil
+		}
+
+		res = append(res, run)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// latest first
+	slices.SortFunc(res, func(a, b *Run) int { return b.Start.Compare(a.Start) })
+
+	if len(res) > n {
+		res = res[:n]
+	}
+
+	return res, nil
+}
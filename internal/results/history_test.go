@@ -0,0 +1,75 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	results := map[string]config.TestResult{
+		"read": {Status: config.Pass, Measurements: map[string]float64{"ops": 1000}},
+	}
+
+	for i, run := range []*Run{
+		{Config: "ycsb-workloada.yml", Database: "ferretdb2", Hostname: "runner-1", Start: start},
+		{Config: "ycsb-workloada.yml", Database: "ferretdb2", Hostname: "runner-1", Start: start.Add(time.Hour)},
+		{Config: "ycsb-workloada.yml", Database: "ferretdb2", Hostname: "runner-1", Start: start.Add(2 * time.Hour)},
+		{Config: "ycsb-workloada.yml", Database: "ferretdb2", Hostname: "runner-1", Start: start.Add(3 * time.Hour)},
+		{Config: "ycsb-workloada.yml", Database: "mongodb", Hostname: "runner-1", Start: start},
+		{Config: "ycsb-workloadb.yml", Database: "ferretdb2", Hostname: "runner-1", Start: start},
+	} {
+		run.Results = results
+
+		_, err := Save(filepath.Join(dir, "run"+string(rune('a'+i))), run)
+		require.NoError(t, err)
+	}
+
+	// results of a shard without tests
+	_, err := Save(filepath.Join(dir, "empty"), &Run{
+		Config:   "ycsb-workloada.yml",
+		Database: "ferretdb2",
+		Hostname: "runner-1",
+		Start:    start.Add(150 * time.Minute),
+		Shard:    &config.Shard{Index: 2, Total: 2},
+		Results:  map[string]config.TestResult{},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "read.json"), []byte(`{"ops": 1}`), 0o666))
+
+	runs, err := History(dir, "ycsb-workloada.yml", "ferretdb2", "runner-1", start.Add(3*time.Hour), 2)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, start.Add(2*time.Hour), runs[0].Start)
+	assert.Equal(t, start.Add(time.Hour), runs[1].Start)
+
+	runs, err = History(dir, "ycsb-workloada.yml", "ferretdb2", "runner-2", start.Add(3*time.Hour), 2)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
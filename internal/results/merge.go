@@ -0,0 +1,99 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// Merge combines results of all shards of the same project configuration and database into a single run.
+//
+// All shards should be present exactly once and use the same test name filters.
+func Merge(runs []*Run) (*Run, error) {
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no results to merge")
+	}
+
+	first := runs[0]
+
+	if first.Shard == nil {
+		return nil, fmt.Errorf("%s / %s: results are not sharded", first.Config, first.Database)
+	}
+
+	res := &Run{
+		Config:     first.Config,
+		Database:   first.Database,
+		Runner:     first.Runner,
+		Start:      first.Start,
+		FilterRun:  first.FilterRun,
+		FilterSkip: first.FilterSkip,
+		Hostname:   first.Hostname,
+		Repository: first.Repository,
+		Commit:     first.Commit,
+		Results:    make(map[string]config.TestResult),
+	}
+
+	end := first.Start.Add(first.Duration)
+	seen := make([]bool, first.Shard.Total)
+
+	for _, run := range runs {
+		if filepath.Base(run.Config) != filepath.Base(first.Config) || run.Database != first.Database {
+			return nil, fmt.Errorf("%s / %s: can't merge with %s / %s", run.Config, run.Database, first.Config, first.Database)
+		}
+
+		if run.Shard == nil || run.Shard.Total != first.Shard.Total {
+			return nil, fmt.Errorf("%s / %s: different number of shards", run.Config, run.Database)
+		}
+
+		if seen[run.Shard.Index-1] {
+			return nil, fmt.Errorf("%s / %s: duplicate shard %s", run.Config, run.Database, run.Shard)
+		}
+
+		seen[run.Shard.Index-1] = true
+
+		if run.FilterRun != first.FilterRun || run.FilterSkip != first.FilterSkip {
+			return nil, fmt.Errorf("%s / %s: different test name filters", run.Config, run.Database)
+		}
+
+		for _, t := range slices.Sorted(maps.Keys(run.Results)) {
+			if _, ok := res.Results[t]; ok {
+				return nil, fmt.Errorf("%s / %s: test %q is present in several shards", run.Config, run.Database, t)
+			}
+
+			res.Results[t] = run.Results[t]
+		}
+
+		if run.Start.Before(res.Start) {
+			res.Start = run.Start
+		}
+
+		if e := run.Start.Add(run.Duration); e.After(end) {
+			end = e
+		}
+	}
+
+	if i := slices.Index(seen, false); i >= 0 {
+		return nil, fmt.Errorf("%s / %s: missing shard %d/%d", first.Config, first.Database, i+1, first.Shard.Total)
+	}
+
+	res.Duration = end.Sub(res.Start)
+
+	return res, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	shard := func(i int, results map[string]config.TestResult) *Run {
+		return &Run{
+			Config:   "mongo-tools.yml",
+			Database: "ferretdb2",
+			Runner:   config.RunnerTypeGoTest,
+			Start:    start.Add(time.Duration(i) * time.Minute),
+			Duration: time.Minute,
+			Shard:    &config.Shard{Index: i, Total: 2},
+			Results:  results,
+		}
+	}
+
+	shard1 := shard(1, map[string]config.TestResult{
+		"pkg/TestA": {Status: config.Pass},
+	})
+	shard2 := shard(2, map[string]config.TestResult{
+		"pkg/TestB":     {Status: config.Fail},
+		"pkg/TestB/Sub": {Status: config.Fail},
+	})
+
+	actual, err := Merge([]*Run{shard2, shard1})
+	require.NoError(t, err)
+
+	expected := &Run{
+		Config:   "mongo-tools.yml",
+		Database: "ferretdb2",
+		Runner:   config.RunnerTypeGoTest,
+		Start:    start.Add(time.Minute),
+		Duration: 2 * time.Minute,
+		Results: map[string]config.TestResult{
+			"pkg/TestA":     {Status: config.Pass},
+			"pkg/TestB":     {Status: config.Fail},
+			"pkg/TestB/Sub": {Status: config.Fail},
+		},
+	}
+	assert.Equal(t, expected, actual)
+
+	// shards without tests save empty results
+	actual, err = Merge([]*Run{shard(1, map[string]config.TestResult{}), shard2})
+	require.NoError(t, err)
+	assert.Len(t, actual.Results, 2)
+
+	_, err = Merge([]*Run{shard1})
+	require.EqualError(t, err, "mongo-tools.yml / ferretdb2: missing shard 2/2")
+
+	_, err = Merge([]*Run{shard1, shard1})
+	require.EqualError(t, err, "mongo-tools.yml / ferretdb2: duplicate shard 1/2")
+
+	shard2.Results["pkg/TestA"] = config.TestResult{Status: config.Pass}
+	_, err = Merge([]*Run{shard1, shard2})
+	require.EqualError(t, err, `mongo-tools.yml / ferretdb2: test "pkg/TestA" is present in several shards`)
+
+	shard2.Database = "mongodb"
+	_, err = Merge([]*Run{shard1, shard2})
+	require.EqualError(t, err, "mongo-tools.yml / mongodb: can't merge with mongo-tools.yml / ferretdb2")
+}
@@ -0,0 +1,193 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package results provides saving and loading of raw runner results.
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+// Run represents raw results of a single project configuration and database pair run with metadata.
+//
+//nolint:vet // for readability
+type Run struct {
+	Config   string
+	Database string
+	Runner   config.RunnerType
+	Start    time.Time
+	Duration time.Duration
+
+	// test name filters of partial runs
+	FilterRun  string
+	FilterSkip string
+
+	// part of tests of sharded runs
+	Shard *config.Shard
+
+	// environment
+	Hostname   string
+	Repository string
+	Commit     string
+
+	Results map[string]config.TestResult
+}
+
+// jsonRun represents [Run] in the file.
+//
+//nolint:vet // for readability
+type jsonRun struct {
+	Config          string    `json:"config"`
+	Database        string    `json:"database"`
+	Runner          string    `json:"runner"`
+	Start           time.Time `json:"start"`
+	DurationSeconds float64   `json:"duration_seconds"`
+
+	FilterRun  string `json:"filter_run,omitempty"`
+	FilterSkip string `json:"filter_skip,omitempty"`
+	Shard      string `json:"shard,omitempty"`
+
+	Hostname   string `json:"hostname,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+
+	Results map[string]jsonTest `json:"results"`
+}
+
+// jsonTest represents [config.TestResult] in the file.
+type jsonTest struct {
+	Status       config.Status      `json:"status"`
+	Output       string             `json:"output,omitempty"`
+	Measurements map[string]float64 `json:"measurements,omitempty"`
+	Attempts     int                `json:"attempts,omitempty"`
+	TimedOut     bool               `json:"timed_out,omitempty"`
+}
+
+// FileName returns the results file name for the given project configuration file and database.
+func FileName(configFile, db string) string {
+	return strings.TrimSuffix(filepath.Base(configFile), filepath.Ext(configFile)) + "_" + db + ".json"
+}
+
+// shardFileName returns the results file name for the given shard.
+func shardFileName(configFile, db string, shard *config.Shard) string {
+	name := strings.TrimSuffix(FileName(configFile, db), ".json")
+	return fmt.Sprintf("%s_shard%dof%d.json", name, shard.Index, shard.Total)
+}
+
+// Save writes run results to the file in the given directory and returns the file path.
+func Save(dir string, run *Run) (string, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return "", err
+	}
+
+	doc := jsonRun{
+		Config:          run.Config,
+		Database:        run.Database,
+		Runner:          string(run.Runner),
+		Start:           run.Start.UTC(),
+		DurationSeconds: run.Duration.Seconds(),
+		FilterRun:       run.FilterRun,
+		FilterSkip:      run.FilterSkip,
+		Hostname:        run.Hostname,
+		Repository:      run.Repository,
+		Commit:          run.Commit,
+		Results:         make(map[string]jsonTest, len(run.Results)),
+	}
+
+	if run.Shard != nil {
+		doc.Shard = run.Shard.String()
+	}
+
+	for t, tr := range run.Results {
+		doc.Results[t] = jsonTest{
+			Status:       tr.Status,
+			Output:       tr.Output,
+			Measurements: tr.Measurements,
+			Attempts:     tr.Attempts,
+			TimedOut:     tr.TimedOut,
+		}
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, FileName(run.Config, run.Database))
+	if run.Shard != nil {
+		file = filepath.Join(dir, shardFileName(run.Config, run.Database, run.Shard))
+	}
+
+	if err = os.WriteFile(file, append(b, '\n'), 0o666); err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// Load reads run results from the file.
+func Load(file string) (*Run, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+
+	var doc jsonRun
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse results file %s: %w", file, err)
+	}
+
+	if doc.Config == "" || doc.Database == "" {
+		return nil, fmt.Errorf("no config or database in results file %s", file)
+	}
+
+	res := &Run{
+		Config:     doc.Config,
+		Database:   doc.Database,
+		Runner:     config.RunnerType(doc.Runner),
+		Start:      doc.Start,
+		Duration:   time.Duration(doc.DurationSeconds * float64(time.Second)),
+		FilterRun:  doc.FilterRun,
+		FilterSkip: doc.FilterSkip,
+		Hostname:   doc.Hostname,
+		Repository: doc.Repository,
+		Commit:     doc.Commit,
+		Results:    make(map[string]config.TestResult, len(doc.Results)),
+	}
+
+	if doc.Shard != "" {
+		if res.Shard, err = config.ParseShard(doc.Shard); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	for t, jt := range doc.Results {
+		res.Results[t] = config.TestResult{
+			Status:       jt.Status,
+			Output:       jt.Output,
+			Measurements: jt.Measurements,
+			Attempts:     jt.Attempts,
+			TimedOut:     jt.TimedOut,
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/dance/internal/config"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "results")
+
+	run := &Run{
+		Config:     "ycsb-workloada.yml",
+		Database:   "ferretdb2",
+		Runner:     config.RunnerTypeYCSB,
+		Start:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Duration:   1500 * time.Millisecond,
+		FilterRun:  "read",
+		Hostname:   "runner-1",
+		Repository: "FerretDB/dance",
+		Results: map[string]config.TestResult{
+			"read": {
+				Status:       config.Pass,
+				Measurements: map[string]float64{"ops": 1234.5},
+			},
+			"update": {
+				Status:   config.Fail,
+				Output:   "line 1\nline 2",
+				Attempts: 2,
+				TimedOut: true,
+			},
+		},
+	}
+
+	file, err := Save(dir, run)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "ycsb-workloada_ferretdb2.json"), file)
+
+	actual, err := Load(file)
+	require.NoError(t, err)
+	assert.Equal(t, run, actual)
+
+	run.Shard = &config.Shard{Index: 2, Total: 3}
+
+	file, err = Save(dir, run)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "ycsb-workloada_ferretdb2_shard2of3.json"), file)
+
+	actual, err = Load(file)
+	require.NoError(t, err)
+	assert.Equal(t, run, actual)
+
+	_, err = Load(filepath.Join(dir, "missing.json"))
+	require.ErrorContains(t, err, "failed to read results file")
+}
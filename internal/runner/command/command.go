@@ -17,18 +17,23 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/FerretDB/dance/internal/config"
 	"github.com/FerretDB/dance/internal/runner"
 )
 
+// waitDelay is the time to wait for the output of killed scripts to be closed.
+const waitDelay = 5 * time.Second
+
 // command represents a generic test runner.
 type command struct {
 	p       *config.RunnerParamsCommand
@@ -46,8 +51,9 @@ func New(params *config.RunnerParamsCommand, l *slog.Logger, verbose bool) (runn
 }
 
 // execScripts stores the given shell script content in dir/file-XXX.sh and executes it.
-// It returns the combined output of the script execution.
-func execScript(ctx context.Context, dir, file, content string, verbose bool) ([]byte, error) {
+// It returns the combined output of the script execution;
+// in verbose mode, it is also logged at INFO level.
+func execScript(ctx context.Context, l *slog.Logger, dir, file, content string, verbose bool) ([]byte, error) {
 	if dir == "" {
 		dir = "."
 	}
@@ -82,11 +88,17 @@ func execScript(ctx context.Context, dir, file, content string, verbose bool) ([
 	cmd := exec.CommandContext(ctx, "./"+filepath.Base(f.Name()))
 	cmd.Dir = dir
 
+	// do not wait forever for child processes that keep output open after the script is killed
+	cmd.WaitDelay = waitDelay
+
 	var b runner.LockedBuffer
 
 	if verbose {
-		cmd.Stdout = io.MultiWriter(&b, os.Stdout)
-		cmd.Stderr = io.MultiWriter(&b, os.Stderr)
+		lw := runner.NewLogWriter(ctx, l, slog.LevelInfo)
+		defer lw.Close()
+
+		cmd.Stdout = io.MultiWriter(&b, lw)
+		cmd.Stderr = cmd.Stdout
 	} else {
 		cmd.Stdout = &b
 		cmd.Stderr = &b
@@ -97,13 +109,41 @@ func execScript(ctx context.Context, dir, file, content string, verbose bool) ([
 }
 
 // Run implements [runner.Runner] interface.
-func (c *command) Run(ctx context.Context) (res map[string]config.TestResult, err error) {
+func (c *command) Run(ctx context.Context) (map[string]config.TestResult, error) {
+	return c.run(ctx, c.p.Tests)
+}
+
+// Rerun implements [runner.Rerunner] interface.
+//
+// Setup and teardown are run again.
+func (c *command) Rerun(ctx context.Context, tests []string) (map[string]config.TestResult, error) {
+	ts := slices.DeleteFunc(slices.Clone(c.p.Tests), func(t config.RunnerParamsCommandTest) bool {
+		return !slices.Contains(tests, t.Name)
+	})
+
+	return c.run(ctx, ts)
+}
+
+// List implements [runner.Lister] interface.
+func (c *command) List(ctx context.Context) ([]string, error) {
+	res := make([]string, len(c.p.Tests))
+	for i, t := range c.p.Tests {
+		res[i] = t.Name
+	}
+
+	slices.Sort(res)
+
+	return res, nil
+}
+
+// run executes setup, given tests, and teardown.
+func (c *command) run(ctx context.Context, tests []config.RunnerParamsCommandTest) (res map[string]config.TestResult, err error) {
 	var b []byte
 
 	if c.p.Setup != "" {
 		c.l.InfoContext(ctx, "Running setup")
 
-		if b, err = execScript(ctx, c.p.Dir, "setup", c.p.Setup, c.verbose); err != nil {
+		if b, err = execScript(ctx, c.l, c.p.Dir, "setup", c.p.Setup, c.verbose); err != nil {
 			err = fmt.Errorf("%s\n%w", b, err)
 
 			return
@@ -115,26 +155,52 @@ func (c *command) Run(ctx context.Context) (res map[string]config.TestResult, er
 			c.l.InfoContext(ctx, "Running teardown")
 
 			// canceled context should not prevent teardown
-			if b, err = execScript(context.WithoutCancel(ctx), c.p.Dir, "teardown", c.p.Teardown, c.verbose); err != nil {
+			if b, err = execScript(context.WithoutCancel(ctx), c.l, c.p.Dir, "teardown", c.p.Teardown, c.verbose); err != nil {
 				err = fmt.Errorf("%s\n%w", b, err)
 			}
 		}()
 	}
 
-	res = c.runTests(ctx)
+	res = c.runTests(ctx, tests)
 
 	return
 }
 
-// runTests executes tests and returns the results.
-func (c *command) runTests(ctx context.Context) map[string]config.TestResult {
-	res := make(map[string]config.TestResult, len(c.p.Tests))
+// runTests executes given tests and returns the results.
+func (c *command) runTests(ctx context.Context, tests []config.RunnerParamsCommandTest) map[string]config.TestResult {
+	res := make(map[string]config.TestResult, len(tests))
+
+	for i, t := range tests {
+		if err := context.Cause(ctx); err != nil {
+			c.l.WarnContext(ctx, "Not running remaining tests", slog.String("error", err.Error()))
+
+			// record them, so reports show why they are missing
+			for _, t := range tests[i:] {
+				res[t.Name] = config.TestResult{
+					Status:   config.Fail,
+					Output:   "not run: " + err.Error(),
+					TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+				}
+			}
+
+			break
+		}
 
-	for _, t := range c.p.Tests {
 		start := time.Now()
 		c.l.InfoContext(ctx, "Running test", slog.String("test", t.Name))
 
-		b, err := execScript(ctx, c.p.Dir, t.Name, t.Cmd, c.verbose)
+		tctx, cancel := ctx, context.CancelFunc(func() {})
+		if t.Timeout > 0 {
+			tctx, cancel = context.WithTimeoutCause(ctx, t.Timeout, fmt.Errorf("test timed out after %s", t.Timeout))
+		}
+
+		b, err := execScript(tctx, c.l.With(slog.String("test", t.Name)), c.p.Dir, t.Name, t.Cmd, c.verbose)
+
+		// that includes the timeout of the whole run
+		timedOut := errors.Is(tctx.Err(), context.DeadlineExceeded)
+		cause := context.Cause(tctx)
+
+		cancel()
 
 		tc := config.TestResult{
 			Status: config.Pass,
@@ -148,6 +214,11 @@ func (c *command) runTests(ctx context.Context) map[string]config.TestResult {
 
 			tc.Status = config.Fail
 			tc.Output += "\n" + err.Error()
+
+			if timedOut {
+				tc.TimedOut = true
+				tc.Output += "\n" + cause.Error()
+			}
 		} else {
 			c.l.InfoContext(ctx, "Test passed", args...)
 		}
@@ -160,5 +231,7 @@ func (c *command) runTests(ctx context.Context) map[string]config.TestResult {
 
 // check interfaces
 var (
-	_ runner.Runner = (*command)(nil)
+	_ runner.Runner   = (*command)(nil)
+	_ runner.Rerunner = (*command)(nil)
+	_ runner.Lister   = (*command)(nil)
 )
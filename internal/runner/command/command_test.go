@@ -16,13 +16,16 @@ package command
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/runner"
 )
 
 func TestCommand(t *testing.T) {
@@ -114,4 +117,94 @@ func TestCommand(t *testing.T) {
 		_, err = c.Run(ctx)
 		require.ErrorContains(t, err, "exit status 3")
 	})
+	t.Run("Rerun", func(t *testing.T) {
+		p := &config.RunnerParamsCommand{Tests: tests}
+		c, err := New(p, slog.Default(), false)
+		require.NoError(t, err)
+
+		res, err := c.(runner.Rerunner).Rerun(ctx, []string{"test2"})
+		require.NoError(t, err)
+
+		expected := map[string]config.TestResult{
+			"test2": {
+				Status: "fail",
+				Output: "\nexit status 1",
+			},
+		}
+		assert.Equal(t, expected, res)
+	})
+	t.Run("List", func(t *testing.T) {
+		p := &config.RunnerParamsCommand{Tests: tests}
+		c, err := New(p, slog.Default(), false)
+		require.NoError(t, err)
+
+		res, err := c.(runner.Lister).List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"test1", "test2"}, res)
+	})
+	t.Run("Timeout", func(t *testing.T) {
+		p := &config.RunnerParamsCommand{
+			Tests: []config.RunnerParamsCommandTest{
+				{
+					Name:    "slow",
+					Cmd:     "echo started; exec sleep 10",
+					Timeout: 100 * time.Millisecond,
+				},
+				tests[0],
+			},
+		}
+
+		c, err := New(p, slog.Default(), false)
+		require.NoError(t, err)
+
+		res, err := c.Run(ctx)
+		require.NoError(t, err)
+
+		expected := map[string]config.TestResult{
+			"slow": {
+				Status:   "fail",
+				Output:   "started\n\nsignal: killed\ntest timed out after 100ms",
+				TimedOut: true,
+			},
+			"test1": {
+				Status: "pass",
+				Output: "",
+			},
+		}
+		assert.Equal(t, expected, res)
+	})
+	t.Run("RunTimeout", func(t *testing.T) {
+		p := &config.RunnerParamsCommand{
+			Tests: []config.RunnerParamsCommandTest{
+				{
+					Name: "slow",
+					Cmd:  "echo started; exec sleep 10",
+				},
+				tests[0],
+			},
+		}
+
+		c, err := New(p, slog.Default(), false)
+		require.NoError(t, err)
+
+		rctx, cancel := context.WithTimeoutCause(ctx, 100*time.Millisecond, errors.New("configuration timed out after 100ms"))
+		defer cancel()
+
+		res, err := c.Run(rctx)
+		require.NoError(t, err)
+
+		expected := map[string]config.TestResult{
+			"slow": {
+				Status:   "fail",
+				Output:   "started\n\nsignal: killed\nconfiguration timed out after 100ms",
+				TimedOut: true,
+			},
+			"test1": {
+				Status:   "fail",
+				Output:   "not run: configuration timed out after 100ms",
+				TimedOut: true,
+			},
+		}
+		assert.Equal(t, expected, res)
+	})
 }
@@ -0,0 +1,227 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotest
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// path represents a single alternative of `go test -run` regular expressions.
+type path struct {
+	top []*regexp.Regexp // top-level test name should match all of them
+	sub []string         // regular expressions for levels of subtest names, if any
+}
+
+// splitRun splits `go test -run` regular expression into alternatives of slash-separated levels
+// the same way the testing package does: on `|` and `/` outside of brackets and parentheses.
+func splitRun(re string) [][]string {
+	var res [][]string
+	var levels []string
+	var brackets, parens int
+
+	for i := 0; i < len(re); {
+		switch re[i] {
+		case '[':
+			brackets++
+		case ']':
+			if brackets--; brackets < 0 {
+				brackets = 0
+			}
+		case '(':
+			if brackets == 0 {
+				parens++
+			}
+		case ')':
+			if brackets == 0 {
+				parens--
+			}
+		case '\\':
+			i++
+		case '/', '|':
+			if brackets == 0 && parens == 0 {
+				levels = append(levels, re[:i])
+
+				if re[i] == '|' {
+					res = append(res, levels)
+					levels = nil
+				}
+
+				re = re[i+1:]
+				i = 0
+
+				continue
+			}
+		}
+
+		i++
+	}
+
+	return append(res, append(levels, re))
+}
+
+// parseRun returns paths of `go test -run` regular expression.
+func parseRun(re string) ([]path, error) {
+	var res []path
+
+	for _, levels := range splitRun(re) {
+		top, err := regexp.Compile(levels[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid -run regular expression %q: %w", re, err)
+		}
+
+		for _, sub := range levels[1:] {
+			if _, err = regexp.Compile(sub); err != nil {
+				return nil, fmt.Errorf("invalid -run regular expression %q: %w", re, err)
+			}
+		}
+
+		res = append(res, path{top: []*regexp.Regexp{top}, sub: levels[1:]})
+	}
+
+	return res, nil
+}
+
+// selectPaths returns paths of tests selected by both `-run` regular expressions, or nil if both are empty.
+//
+// Top-level names should match both; regular expressions for subtests can't be combined,
+// so only one of them may contain them.
+func selectPaths(run1, run2 string) ([]path, error) {
+	if run1 == "" && run2 == "" {
+		return nil, nil
+	}
+
+	if run1 == "" {
+		run1, run2 = run2, run1
+	}
+
+	paths1, err := parseRun(run1)
+	if err != nil {
+		return nil, err
+	}
+
+	if run2 == "" {
+		return paths1, nil
+	}
+
+	paths2, err := parseRun(run2)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []path
+
+	for _, p1 := range paths1 {
+		for _, p2 := range paths2 {
+			if len(p1.sub) > 0 && len(p2.sub) > 0 {
+				return nil, fmt.Errorf("can't combine -run regular expressions %q and %q for subtests", run1, run2)
+			}
+
+			p := path{
+				top: append(append([]*regexp.Regexp(nil), p1.top...), p2.top...),
+				sub: p1.sub,
+			}
+
+			if len(p.sub) == 0 {
+				p.sub = p2.sub
+			}
+
+			res = append(res, p)
+		}
+	}
+
+	return res, nil
+}
+
+// matchTop returns true if the given top-level test name matches the path.
+func (p *path) matchTop(name string) bool {
+	for _, re := range p.top {
+		if !re.MatchString(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runRE returns `go test -run` regular expression for exactly given top-level tests and subtests of the path.
+func (p *path) runRE(top []string) string {
+	quoted := make([]string, len(top))
+	for i, name := range top {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+
+	res := "^(" + strings.Join(quoted, "|") + ")$"
+	for _, sub := range p.sub {
+		res += "/" + sub
+	}
+
+	return res
+}
+
+// rerunRE returns `go test -run` regular expression that selects exactly given tests and their parents.
+//
+// Tests that are parents of other given tests select only those subtests.
+func rerunRE(names []string) string {
+	var res []string
+
+	for _, name := range names {
+		if slices.ContainsFunc(names, func(n string) bool { return strings.HasPrefix(n, name+"/") }) {
+			continue
+		}
+
+		levels := strings.Split(name, "/")
+		for i, l := range levels {
+			levels[i] = "^" + regexp.QuoteMeta(l) + "$"
+		}
+
+		if re := strings.Join(levels, "/"); !slices.Contains(res, re) {
+			res = append(res, re)
+		}
+	}
+
+	slices.Sort(res)
+
+	return strings.Join(res, "|")
+}
+
+// argValue returns the last value of `go test` flag with the given name in arguments, or empty string.
+func argValue(args []string, name string) string {
+	var res string
+
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+
+		arg := strings.TrimPrefix(args[i], "-")
+		arg = strings.TrimPrefix(arg, "-")
+		arg = strings.TrimPrefix(arg, "test.")
+
+		if v, ok := strings.CutPrefix(arg, name+"="); ok {
+			res = v
+			continue
+		}
+
+		if arg == name && i+1 < len(args) {
+			i++
+			res = args[i]
+		}
+	}
+
+	return res
+}
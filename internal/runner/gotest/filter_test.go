@@ -0,0 +1,113 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gotest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitRun(t *testing.T) {
+	t.Parallel()
+
+	for re, expected := range map[string][][]string{
+		"":                 {{""}},
+		"Test1":            {{"Test1"}},
+		"Test2/Sub":        {{"Test2", "Sub"}},
+		"Test1|Test2/Sub":  {{"Test1"}, {"Test2", "Sub"}},
+		"(Test1|Test2)/A":  {{"(Test1|Test2)", "A"}},
+		"Test[/|]x/A|B":    {{"Test[/|]x", "A"}, {"B"}},
+		`Test\/x|Test\|y`:  {{`Test\/x`}, {`Test\|y`}},
+		"Test1//Sub/":      {{"Test1", "", "Sub", ""}},
+		"^(Test1|Test2)$/": {{"^(Test1|Test2)$", ""}},
+	} {
+		t.Run(re, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, expected, splitRun(re))
+		})
+	}
+}
+
+func TestArgValue(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		args     []string
+		expected string
+	}{
+		"None": {
+			args: []string{"-timeout=20m", "-shuffle=on"},
+		},
+		"Equals": {
+			args:     []string{"-run=Test1"},
+			expected: "Test1",
+		},
+		"Separate": {
+			args:     []string{"-run", "Test1"},
+			expected: "Test1",
+		},
+		"Prefixed": {
+			args:     []string{"--test.run=Test1"},
+			expected: "Test1",
+		},
+		"Last": {
+			args:     []string{"-run=Test1", "-v", "-run", "Test2"},
+			expected: "Test2",
+		},
+		"Value": {
+			args: []string{"-uri=run=x", "run=Test1"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, argValue(tc.args, "run"))
+		})
+	}
+}
+
+func TestRerunRE(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		names    []string
+		expected string
+	}{
+		"Top": {
+			names:    []string{"Test1"},
+			expected: `^Test1$`,
+		},
+		"Subtest": {
+			names:    []string{"Test2/Sub"},
+			expected: `^Test2$/^Sub$`,
+		},
+		"Parent": {
+			names:    []string{"Test2", "Test2/Sub", "Test2/Sub/A|B"},
+			expected: `^Test2$/^Sub$/^A\|B$`,
+		},
+		"Many": {
+			names:    []string{"Test2/Sub", "Test1", "Test2/Sub2", "Test2/Sub"},
+			expected: `^Test1$|^Test2$/^Sub$|^Test2$/^Sub2$`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, rerunRE(tc.names))
+		})
+	}
+}
@@ -16,14 +16,16 @@
 package gotest
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"os"
 	"os/exec"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -48,11 +50,20 @@ func (te testEvent) Elapsed() time.Duration {
 	return time.Duration(te.ElapsedSeconds * float64(time.Second))
 }
 
+// testNameRE matches names of tests, examples, and fuzz tests printed by `go test -list`.
+var testNameRE = regexp.MustCompile(`^(Test|Example|Fuzz)\w*$`)
+
+// waitDelay is the time to wait for the output of killed `go test` to be closed.
+const waitDelay = 5 * time.Second
+
 // goTest represents `gotest` runner.
 type goTest struct {
 	p       *config.RunnerParamsGoTest
 	l       *slog.Logger
 	verbose bool
+
+	// Go test names (without package) by full test names of the previous run
+	names map[string]string
 }
 
 // New creates a new `gotest` runner with given parameters.
@@ -65,16 +76,213 @@ func New(params *config.RunnerParamsGoTest, l *slog.Logger, verbose bool) (runne
 }
 
 // Run implements [runner.Runner] interface.
+//
+// Filter and shard are combined with `-run` and `-skip` flags from arguments,
+// so tests that are not selected by arguments are never run.
 func (c *goTest) Run(ctx context.Context) (map[string]config.TestResult, error) {
+	args := c.args()
+
+	argsRun := argValue(c.p.Args, "run")
+
+	// the last flag wins, so there is nothing to combine
+	if c.p.Shard == nil && (argsRun == "" || c.p.Run == "") {
+		if c.p.Run != "" {
+			args = append(args, "-run="+c.p.Run)
+		}
+
+		return c.run(ctx, args)
+	}
+
+	paths, err := selectPaths(argsRun, c.p.Run)
+	if err != nil {
+		return nil, err
+	}
+
+	if paths == nil {
+		paths = []path{{}}
+	}
+
+	listed, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var run []string
+	selected := make(map[string]struct{})
+
+	for _, p := range paths {
+		var top []string
+
+		for test, name := range listed {
+			if p.matchTop(name) && (c.p.Shard == nil || c.p.Shard.Match(test)) && !slices.Contains(top, name) {
+				top = append(top, name)
+				selected[name] = struct{}{}
+			}
+		}
+
+		if len(top) > 0 {
+			slices.Sort(top)
+			run = append(run, p.runRE(top))
+		}
+	}
+
+	if c.p.Shard != nil {
+		c.l.InfoContext(
+			ctx, "Running top-level tests of shard",
+			slog.String("shard", c.p.Shard.String()), slog.Int("tests", len(selected)),
+		)
+	}
+
+	if len(run) == 0 {
+		return map[string]config.TestResult{}, nil
+	}
+
+	return c.run(ctx, append(args, "-run="+strings.Join(run, "|")))
+}
+
+// List implements [runner.Lister] interface.
+//
+// Only top-level tests (of the shard, if set) are listed.
+func (c *goTest) List(ctx context.Context) ([]string, error) {
+	paths, err := selectPaths(argValue(c.p.Args, "run"), c.p.Run)
+	if err != nil {
+		return nil, err
+	}
+
+	listed, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+
+	for test, name := range listed {
+		if c.p.Shard != nil && !c.p.Shard.Match(test) {
+			continue
+		}
+
+		if paths == nil || slices.ContainsFunc(paths, func(p path) bool { return p.matchTop(name) }) {
+			res = append(res, test)
+		}
+	}
+
+	slices.Sort(res)
+
+	return res, nil
+}
+
+// list returns Go names of all top-level tests by full test names.
+func (c *goTest) list(ctx context.Context) (map[string]string, error) {
+	// -run flag in arguments does not affect -list
+	args := append([]string{"test", "-json", "-list=."}, c.p.Args...)
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = c.p.Dir
+	stderr := runner.NewLogWriter(ctx, c.l, slog.LevelWarn)
+	defer stderr.Close()
+
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+
+	c.l.InfoContext(ctx, "Listing tests", slog.String("cmd", strings.Join(cmd.Args, " ")))
+
+	b, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tests: %w", err)
+	}
+
+	res := make(map[string]string)
+
+	d := json.NewDecoder(bytes.NewReader(b))
+
+	for {
+		var event testEvent
+		if err = d.Decode(&event); err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return nil, fmt.Errorf("failed to list tests: %w", err)
+		}
+
+		// skip package result lines like "ok  \tpkg\t0.01s" and benchmarks
+		name := strings.TrimSuffix(event.Output, "\n")
+		if event.Action != "output" || !testNameRE.MatchString(name) {
+			continue
+		}
+
+		res[event.Package+"/"+name] = name
+	}
+
+	return res, nil
+}
+
+// Rerun implements [runner.Rerunner] interface.
+//
+// Only given tests (and their parents, but not other subtests) are run again with `-run` flag;
+// only results of given tests are returned.
+func (c *goTest) Rerun(ctx context.Context, tests []string) (map[string]config.TestResult, error) {
+	names := make([]string, len(tests))
+
+	for i, test := range tests {
+		name, ok := c.names[test]
+		if !ok {
+			return nil, fmt.Errorf("unknown test %q", test)
+		}
+
+		names[i] = name
+	}
+
+	args := append(c.args(), "-run="+rerunRE(names))
+
+	all, err := c.run(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]config.TestResult, len(tests))
+
+	for _, test := range tests {
+		if tr, ok := all[test]; ok {
+			res[test] = tr
+		}
+	}
+
+	return res, nil
+}
+
+// args returns `go test` arguments with `-skip` flag that combines arguments and filter.
+func (c *goTest) args() []string {
+	args := slices.Clone(c.p.Args)
+
+	if c.p.Skip == "" {
+		return args
+	}
+
+	// alternatives of -skip are skipped separately, so that is a union
+	skip := c.p.Skip
+	if s := argValue(c.p.Args, "skip"); s != "" {
+		skip = s + "|" + skip
+	}
+
+	return append(args, "-skip="+skip)
+}
+
+// run runs `go test` with given additional arguments and returns parsed results.
+func (c *goTest) run(ctx context.Context, extraArgs []string) (map[string]config.TestResult, error) {
 	// TODO https://github.com/FerretDB/dance/issues/20
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	args := append([]string{"test", "-v", "-json", "-count=1"}, c.p.Args...)
+	args := append([]string{"test", "-v", "-json", "-count=1"}, extraArgs...)
 
 	cmd := exec.CommandContext(ctx, "go", args...)
 	cmd.Dir = c.p.Dir
-	cmd.Stderr = os.Stderr
+	stderr := runner.NewLogWriter(ctx, c.l, slog.LevelWarn)
+	defer stderr.Close()
+
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
 
 	p, err := cmd.StdoutPipe()
 	if err != nil {
@@ -87,6 +295,10 @@ func (c *goTest) Run(ctx context.Context) (map[string]config.TestResult, error)
 		return nil, err
 	}
 
+	// test binaries may keep output open after `go test` is killed
+	stop := context.AfterFunc(ctx, func() { _ = p.Close() })
+	defer stop()
+
 	d := json.NewDecoder(p)
 	d.DisallowUnknownFields()
 
@@ -95,7 +307,7 @@ func (c *goTest) Run(ctx context.Context) (map[string]config.TestResult, error)
 	for {
 		var event testEvent
 		if err = d.Decode(&event); err != nil {
-			if err == io.EOF {
+			if err == io.EOF || ctx.Err() != nil {
 				break
 			}
 
@@ -114,6 +326,12 @@ func (c *goTest) Run(ctx context.Context) (map[string]config.TestResult, error)
 
 		testName := event.Package + "/" + event.Test
 
+		if c.names == nil {
+			c.names = make(map[string]string)
+		}
+
+		c.names[testName] = event.Test
+
 		result := res[testName]
 		if result.Status == "" {
 			result.Status = config.Unknown
@@ -140,6 +358,22 @@ func (c *goTest) Run(ctx context.Context) (map[string]config.TestResult, error)
 	err = cmd.Wait()
 	c.l.InfoContext(ctx, "Done", slog.String("cmd", strings.Join(cmd.Args, " ")), slog.Any("err", err))
 
+	// keep results of finished tests; running tests are failed
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		for t, tr := range res {
+			if tr.Status != config.Unknown {
+				continue
+			}
+
+			tr.Status = config.Fail
+			tr.TimedOut = true
+			tr.Output += context.Cause(ctx).Error()
+			res[t] = tr
+		}
+
+		return res, nil
+	}
+
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) && exitErr.Exited() {
 		err = nil
@@ -150,5 +384,7 @@ func (c *goTest) Run(ctx context.Context) (map[string]config.TestResult, error)
 
 // check interfaces
 var (
-	_ runner.Runner = (*goTest)(nil)
+	_ runner.Runner   = (*goTest)(nil)
+	_ runner.Rerunner = (*goTest)(nil)
+	_ runner.Lister   = (*goTest)(nil)
 )
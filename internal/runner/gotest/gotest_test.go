@@ -17,22 +17,28 @@ package gotest
 import (
 	"context"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/FerretDB/dance/internal/config"
+	"github.com/FerretDB/dance/internal/runner"
 )
 
 func Test1(t *testing.T) {
 }
 
+func Test2(t *testing.T) {
+	t.Run("Sub", func(t *testing.T) {})
+}
+
 func TestGoTest(t *testing.T) {
 	t.Parallel()
 
 	p := &config.RunnerParamsGoTest{
-		Args: []string{"-run", `Test\d+`},
+		Args: []string{"-run", `Test1`},
 	}
 	c, err := New(p, slog.Default(), true)
 	require.NoError(t, err)
@@ -51,3 +57,209 @@ func TestGoTest(t *testing.T) {
 	}
 	assert.Equal(t, expected, res)
 }
+
+func TestGoTestFilter(t *testing.T) {
+	t.Parallel()
+
+	const pkg = "github.com/FerretDB/dance/internal/runner/gotest/"
+
+	for name, tc := range map[string]struct { //nolint:vet // for readability
+		args     []string
+		run      string
+		skip     string
+		expected []string
+		err      string
+	}{
+		"Run": {
+			run:      `^Test2$`,
+			expected: []string{"Test2", "Test2/Sub"},
+		},
+		"CombinedRun": {
+			args:     []string{"-run", `Test\d+`},
+			run:      `^Test2$`,
+			expected: []string{"Test2", "Test2/Sub"},
+		},
+		"CombinedRunNone": {
+			args:     []string{"-run", `Test1`},
+			run:      `^Test2$`,
+			expected: []string{},
+		},
+		"CombinedRunSubtests": {
+			args:     []string{"-run=Test2/Sub"},
+			run:      `Test\d+`,
+			expected: []string{"Test2", "Test2/Sub"},
+		},
+		"CombinedRunAlternation": {
+			args:     []string{"-run", `Test\d+`},
+			run:      `Test1|Test2/NoSub`,
+			expected: []string{"Test1", "Test2"},
+		},
+		"CombinedRunSubtestsError": {
+			args: []string{"-run=Test2/Sub"},
+			run:  `Test2/Other`,
+			err:  `can't combine -run regular expressions "Test2/Sub" and "Test2/Other" for subtests`,
+		},
+		"CombinedSkip": {
+			args:     []string{"-run", `Test\d+`, "-skip", `Test1`},
+			skip:     `Test2/Sub`,
+			expected: []string{"Test2"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &config.RunnerParamsGoTest{
+				Args: tc.args,
+				Run:  tc.run,
+				Skip: tc.skip,
+			}
+			c, err := New(p, slog.Default(), true)
+			require.NoError(t, err)
+
+			res, err := c.Run(context.Background())
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(res))
+			for test := range res {
+				actual = append(actual, strings.TrimPrefix(test, pkg))
+			}
+
+			assert.ElementsMatch(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestGoTestRerun(t *testing.T) {
+	t.Parallel()
+
+	p := &config.RunnerParamsGoTest{
+		Args: []string{"-run", `Test\d+`},
+	}
+	c, err := New(p, slog.Default(), true)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	res, err := c.Run(ctx)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	res, err = c.(runner.Rerunner).Rerun(ctx, []string{"github.com/FerretDB/dance/internal/runner/gotest/Test2/Sub"})
+	require.NoError(t, err)
+
+	expected := map[string]config.TestResult{
+		"github.com/FerretDB/dance/internal/runner/gotest/Test2/Sub": {
+			Status: "pass",
+			Output: "=== RUN   Test2/Sub\n" +
+				"    --- PASS: Test2/Sub (0.00s)\n",
+		},
+	}
+	assert.Equal(t, expected, res)
+
+	_, err = c.(runner.Rerunner).Rerun(ctx, []string{"Test3"})
+	require.EqualError(t, err, `unknown test "Test3"`)
+}
+
+func TestGoTestList(t *testing.T) {
+	t.Parallel()
+
+	p := &config.RunnerParamsGoTest{
+		Run: `Test\d+`,
+	}
+	c, err := New(p, slog.Default(), true)
+	require.NoError(t, err)
+
+	res, err := c.(runner.Lister).List(context.Background())
+	require.NoError(t, err)
+
+	expected := []string{
+		"github.com/FerretDB/dance/internal/runner/gotest/Test1",
+		"github.com/FerretDB/dance/internal/runner/gotest/Test2",
+	}
+	assert.Equal(t, expected, res)
+}
+
+func TestGoTestShard(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	all := make(map[string]config.TestResult)
+
+	for i := 1; i <= 2; i++ {
+		p := &config.RunnerParamsGoTest{
+			Run:   `Test\d+`,
+			Shard: &config.Shard{Index: i, Total: 2},
+		}
+		c, err := New(p, slog.Default(), true)
+		require.NoError(t, err)
+
+		res, err := c.Run(ctx)
+		require.NoError(t, err)
+
+		for test, tr := range res {
+			assert.NotContains(t, all, test)
+			all[test] = tr
+		}
+	}
+
+	assert.Len(t, all, 3)
+	assert.Contains(t, all, "github.com/FerretDB/dance/internal/runner/gotest/Test2/Sub")
+}
+
+func TestGoTestShardArgs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		args     []string
+		run      string
+		expected []string
+	}{
+		"Args": {
+			args: []string{"-run", `Test2`},
+			expected: []string{
+				"github.com/FerretDB/dance/internal/runner/gotest/Test2",
+				"github.com/FerretDB/dance/internal/runner/gotest/Test2/Sub",
+			},
+		},
+		"Alternation": {
+			run: `Test1|Test2/NoSub`,
+			expected: []string{
+				"github.com/FerretDB/dance/internal/runner/gotest/Test1",
+				"github.com/FerretDB/dance/internal/runner/gotest/Test2",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var all []string
+
+			for i := 1; i <= 2; i++ {
+				p := &config.RunnerParamsGoTest{
+					Args:  tc.args,
+					Run:   tc.run,
+					Shard: &config.Shard{Index: i, Total: 2},
+				}
+				c, err := New(p, slog.Default(), true)
+				require.NoError(t, err)
+
+				res, err := c.Run(ctx)
+				require.NoError(t, err)
+
+				for test := range res {
+					all = append(all, test)
+				}
+			}
+
+			assert.ElementsMatch(t, tc.expected, all)
+		})
+	}
+}
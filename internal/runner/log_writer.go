@@ -0,0 +1,76 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// LogWriter is an [io.Writer] that logs each written line of subprocess output.
+//
+// It is safe for concurrent use, so it could be used for both stdout and stderr.
+type LogWriter struct {
+	ctx   context.Context
+	l     *slog.Logger
+	level slog.Level
+
+	m   sync.Mutex
+	buf []byte
+}
+
+// NewLogWriter creates a new [LogWriter] that logs lines with the given level.
+func NewLogWriter(ctx context.Context, l *slog.Logger, level slog.Level) *LogWriter {
+	return &LogWriter{
+		ctx:   ctx,
+		l:     l,
+		level: level,
+	}
+}
+
+// Write implements [io.Writer] interface.
+func (lw *LogWriter) Write(p []byte) (int, error) {
+	lw.m.Lock()
+	defer lw.m.Unlock()
+
+	lw.buf = append(lw.buf, p...)
+
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		lw.l.Log(lw.ctx, lw.level, string(lw.buf[:i]))
+		lw.buf = lw.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Close logs the last incomplete line, if any.
+func (lw *LogWriter) Close() error {
+	lw.m.Lock()
+	defer lw.m.Unlock()
+
+	if len(lw.buf) > 0 {
+		lw.l.Log(lw.ctx, lw.level, string(lw.buf))
+		lw.buf = nil
+	}
+
+	return nil
+}
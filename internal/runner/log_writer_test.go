@@ -0,0 +1,54 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+
+	lw := NewLogWriter(context.Background(), slog.New(h), slog.LevelInfo)
+
+	_, err := lw.Write([]byte("first\nsec"))
+	require.NoError(t, err)
+
+	_, err = lw.Write([]byte("ond\nthird"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "level=INFO msg=first\nlevel=INFO msg=second\n", buf.String())
+
+	require.NoError(t, lw.Close())
+	assert.Equal(t, "level=INFO msg=first\nlevel=INFO msg=second\nlevel=INFO msg=third\n", buf.String())
+}
@@ -27,6 +27,18 @@ type Runner interface {
 	Run(ctx context.Context) (map[string]config.TestResult, error)
 }
 
+// Rerunner is an optional interface for runners that can run some tests again.
+type Rerunner interface {
+	// Rerun executes given tests of the previous run again and returns results by test name.
+	Rerun(ctx context.Context, tests []string) (map[string]config.TestResult, error)
+}
+
+// Lister is an optional interface for runners that can list tests without running them.
+type Lister interface {
+	// List returns sorted names of tests that would be run.
+	List(ctx context.Context) ([]string, error)
+}
+
 // Params is a common interface for all runner parameters.
 //
 //sumtype:decl
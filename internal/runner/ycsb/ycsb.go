@@ -111,10 +111,18 @@ func parseOutput(r io.Reader) (map[string]map[string]float64, error) {
 }
 
 // run runs given command in the given directory and returns parsed results.
-func run(ctx context.Context, args []string, dir string) (map[string]config.TestResult, error) {
+// The output of the command is logged.
+func run(ctx context.Context, l *slog.Logger, args []string, dir string) (map[string]config.TestResult, error) {
 	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 	cmd.Dir = dir
-	cmd.Stderr = os.Stderr
+
+	stderr := runner.NewLogWriter(ctx, l, slog.LevelWarn)
+	defer stderr.Close()
+
+	cmd.Stderr = stderr
+
+	stdout := runner.NewLogWriter(ctx, l, slog.LevelInfo)
+	defer stdout.Close()
 
 	pipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -127,7 +135,7 @@ func run(ctx context.Context, args []string, dir string) (map[string]config.Test
 		return nil, err
 	}
 
-	ms, err := parseOutput(io.TeeReader(pipe, os.Stdout))
+	ms, err := parseOutput(io.TeeReader(pipe, stdout))
 	if err != nil {
 		_ = cmd.Process.Kill()
 		return nil, err
@@ -168,7 +176,7 @@ func (y *ycsb) Run(ctx context.Context) (map[string]config.TestResult, error) {
 
 	y.l.InfoContext(ctx, "Load", slog.String("cmd", strings.Join(args, " ")))
 
-	if _, err = run(ctx, args, y.p.Dir); err != nil {
+	if _, err = run(ctx, y.l, args, y.p.Dir); err != nil {
 		return nil, err
 	}
 
@@ -176,5 +184,5 @@ func (y *ycsb) Run(ctx context.Context) (map[string]config.TestResult, error) {
 
 	y.l.InfoContext(ctx, "Run", slog.String("cmd", strings.Join(args, " ")))
 
-	return run(ctx, args, y.p.Dir)
+	return run(ctx, y.l, args, y.p.Dir)
 }